@@ -2,17 +2,24 @@ package main
 
 import (
 	"context"
-	"errors"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"log/slog"
 	"news-feed-bot/internal/bot"
 	"news-feed-bot/internal/bot/middleware"
 	"news-feed-bot/internal/botkit"
 	"news-feed-bot/internal/config"
+	"news-feed-bot/internal/digest"
+	"news-feed-bot/internal/extract"
 	fetcher "news-feed-bot/internal/fetcher"
+	"news-feed-bot/internal/httpclient"
 	"news-feed-bot/internal/notifier"
+	"news-feed-bot/internal/schedule"
+	"news-feed-bot/internal/scoring"
+	"news-feed-bot/internal/source"
 	"news-feed-bot/internal/storage"
+	"news-feed-bot/internal/subscription"
 	"news-feed-bot/internal/summary"
+	"news-feed-bot/internal/supervisor"
 	"os"
 	"os/signal"
 	"syscall"
@@ -31,37 +38,165 @@ func main() {
 
 	botAPI, err := tgbotapi.NewBotAPI(cfg.TelegramBotToken)
 	if err != nil {
-		log.Error("failed to create bot: %v", err)
+		log.Error("failed to create bot", "err", err)
 		os.Exit(1)
 	}
 
 	articleStorage, err := storage.NewArticleStorage(log)
 	if err != nil {
-		log.Error("failed to create article storage", err)
+		log.Error("failed to create article storage", "err", err)
 		os.Exit(1)
 	}
 
 	sourceStorage, err := storage.NewSourceStorage(log)
 	if err != nil {
-		log.Error("failed to create source storage", err)
+		log.Error("failed to create source storage", "err", err)
 		os.Exit(1)
 	}
 
+	filterStorage, err := storage.NewFilterStorage(log)
+	if err != nil {
+		log.Error("failed to create filter storage", "err", err)
+		os.Exit(1)
+	}
+
+	channelStorage, err := storage.NewChannelStorage(log)
+	if err != nil {
+		log.Error("failed to create channel storage", "err", err)
+		os.Exit(1)
+	}
+
+	subscriptionStorage, err := storage.NewSubscriptionStorage(log)
+	if err != nil {
+		log.Error("failed to create subscription storage", "err", err)
+		os.Exit(1)
+	}
+
+	if err := channelStorage.EnsureChannel(context.Background(), cfg.TelegramChannelID, "default"); err != nil {
+		log.Error("failed to store the configured channel", "err", err)
+		os.Exit(1)
+	}
+
+	httpClients := httpclient.NewPool(
+		httpclient.Defaults{
+			UserAgent: cfg.HTTPUserAgent,
+			Timeout:   cfg.HTTPTimeout,
+		},
+		httpclient.HostLimits{
+			Concurrency:       cfg.HostConcurrency,
+			RequestsPerSecond: cfg.HostRequestsPerSec,
+		},
+	)
+	sourceKinds := source.DefaultRegistry(httpClients)
+	contentExtractor := extract.New(httpClients)
+
+	// The notifier still extracts articles it posts when extraction at
+	// fetch time is disabled.
+	var fetchExtractor fetcher.ContentExtractor
+	if cfg.ExtractContent {
+		fetchExtractor = contentExtractor
+	}
+
 	f := fetcher.New(
 		articleStorage,
 		sourceStorage,
-		cfg.FetchInterval,
+		filterStorage,
+		sourceKinds,
+		httpClients,
+		fetchExtractor,
+		fetcher.Schedule{
+			Tick:            cfg.FetchSchedulerTick,
+			DefaultInterval: cfg.FetchInterval,
+			MinInterval:     cfg.FetchIntervalMin,
+			MaxInterval:     cfg.FetchIntervalMax,
+		},
+		fetcher.HealthPolicy{
+			MaxFailures: cfg.SourceMaxFailures,
+			StaleAfter:  cfg.SourceStaleAfter,
+		},
+		fetcher.RetryPolicy{
+			MaxAttempts:    cfg.Retry.MaxAttempts,
+			InitialBackoff: cfg.Retry.InitialBackoff,
+			MaxBackoff:     cfg.Retry.MaxBackoff,
+		},
+		fetcher.DedupPolicy{
+			Window:      cfg.DedupWindow,
+			MaxDistance: cfg.DedupMaxDistance,
+		},
+		cfg.FetchWorkers,
 		cfg.FilterKeywords,
+		cfg.AllowedLanguages,
 		log,
 	)
 
+	scorer, err := scoring.NewScorer(scoring.Model{
+		FreshnessHalfLife:   cfg.Scoring.FreshnessHalfLife,
+		Keywords:            cfg.Scoring.Keywords,
+		RecentSourceWindow:  cfg.Scoring.RecentSourceWindow,
+		RecentSourcePenalty: cfg.Scoring.RecentSourcePenalty,
+	})
+	if err != nil {
+		log.Error("failed to create article scorer", "err", err)
+		os.Exit(1)
+	}
+
+	ranker := scoring.NewRanker(
+		articleStorage,
+		sourceStorage,
+		channelStorage,
+		scorer,
+		cfg.Scoring.Candidates,
+		10*cfg.NotificationInterval,
+	)
+
+	postingSchedule, err := schedule.New(cfg.Schedule.TimeZone, cfg.Schedule.Windows, cfg.Schedule.Cron)
+	if err != nil {
+		log.Error("failed to create posting schedule", "err", err)
+		os.Exit(1)
+	}
+
 	n := notifier.New(
 		articleStorage,
+		ranker,
+		channelStorage,
+		sourceStorage,
+		contentExtractor,
 		summary.New(cfg.OpenAIKey, cfg.OpenAIPrompt),
 		botAPI,
 		cfg.NotificationInterval,
-		10*cfg.NotificationInterval,
-		cfg.TelegramChannelID,
+		notifier.Cadence{
+			PostsPerTick:        cfg.Cadence.PostsPerTick,
+			SourceSpacing:       cfg.Cadence.SourceSpacing,
+			SourceDailyCap:      cfg.Cadence.SourceDailyCap,
+			CatchUpThreshold:    cfg.Cadence.CatchUpThreshold,
+			CatchUpPostsPerTick: cfg.Cadence.CatchUpPostsPerTick,
+			DrainPostsPerTick:   cfg.Cadence.DrainPostsPerTick,
+		},
+		postingSchedule,
+		log,
+	)
+
+	digestPublisher := digest.New(
+		ranker,
+		channelStorage,
+		botAPI,
+		cfg.Digest.Tick,
+		cfg.Digest.Candidates,
+		postingSchedule.Location(),
+		log,
+	)
+
+	deliverer := subscription.New(
+		articleStorage,
+		subscriptionStorage,
+		sourceStorage,
+		botAPI,
+		cfg.Subscriptions.Interval,
+		cfg.Subscriptions.Lookback,
+		subscription.RateLimit{
+			PerTick: cfg.Subscriptions.PerTick,
+			PerHour: cfg.Subscriptions.PerHour,
+		},
 		log,
 	)
 
@@ -70,11 +205,30 @@ func main() {
 
 	//separate registering views for bot somehow
 	newsBot := botkit.New(log, botAPI)
+	pendingSources := bot.NewPendingSources()
+	searchQueries := bot.NewSearchQueries()
+
 	newsBot.RegisterCmdView("start", bot.ViewCmdStart())
+	newsBot.RegisterCmdView("subscribe",
+		middleware.PrivateOnly(
+			bot.ViewCmdSubscribe(subscriptionStorage, sourceStorage),
+		),
+	)
+	newsBot.RegisterCmdView("unsubscribe",
+		middleware.PrivateOnly(
+			bot.ViewCmdUnsubscribe(subscriptionStorage, sourceStorage),
+		),
+	)
 	newsBot.RegisterCmdView("addsource",
 		middleware.AdminOnly(
 			cfg.TelegramChannelID,
-			bot.ViewCmdAddSource(sourceStorage),
+			bot.ViewCmdAddSource(sourceStorage, sourceKinds, pendingSources),
+		),
+	)
+	newsBot.RegisterCallbackView(bot.CallbackAddSource,
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCallbackAddSource(sourceStorage, sourceKinds, pendingSources),
 		),
 	)
 	newsBot.RegisterCmdView("listsources",
@@ -103,30 +257,163 @@ func main() {
 		),
 	)
 
-	go func(ctx context.Context) {
-		if err := f.Start(ctx); err != nil {
-			if !errors.Is(err, context.Canceled) {
-				log.Error("failed to start fetcher", err)
-				return
-			}
+	newsBot.RegisterCmdView("sourcehealth",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdSourceHealth(sourceStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("exportsources",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdExportSources(sourceStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("search",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdSearch(articleStorage, searchQueries),
+		),
+	)
+	newsBot.RegisterCallbackView(bot.CallbackSearch,
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCallbackSearch(articleStorage, searchQueries),
+		),
+	)
+
+	newsBot.RegisterCmdView("scores",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdScores(ranker, channelStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("setpriority",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdSetPriority(sourceStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("setgroup",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdSetGroup(sourceStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("channels",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdChannels(channelStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("addchannel",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdAddChannel(channelStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("delchannel",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdDeleteChannel(channelStorage, cfg.TelegramChannelID),
+		),
+	)
 
-			log.Info("fetcher stopped")
-		}
-	}(ctx)
+	newsBot.RegisterCmdView("setdigest",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdSetDigest(channelStorage),
+		),
+	)
 
-	go func(ctx context.Context) {
-		if err := n.Start(ctx); err != nil {
-			if !errors.Is(err, context.Canceled) {
-				log.Error("failed to start notifier", err)
-				return
-			}
+	newsBot.RegisterCmdView("addroute",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdAddRoute(channelStorage),
+		),
+	)
 
-			log.Info("notifier stopped")
-		}
-	}(ctx)
+	newsBot.RegisterCmdView("delroute",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdDeleteRoute(channelStorage),
+		),
+	)
 
-	if err := newsBot.Run(ctx); err != nil {
-		log.Error("failed to run botkit", err)
+	newsBot.RegisterCmdView("setlanguages",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdSetLanguages(sourceStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("filters",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdFilters(filterStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("addfilter",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdAddFilter(filterStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("delfilter",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdDeleteFilter(filterStorage),
+		),
+	)
+
+	newsBot.RegisterCmdView("testfilter",
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdTestFilter(articleStorage),
+		),
+	)
+
+	newsBot.RegisterMessageView(bot.IsOPMLDocument,
+		middleware.AdminOnly(
+			cfg.TelegramChannelID,
+			bot.ViewMsgImportOPML(sourceStorage, sourceKinds),
+		),
+	)
+	newsBot.RegisterMessageView(bot.IsChannelForward,
+		middleware.AdminOnlyQuiet(
+			cfg.TelegramChannelID,
+			bot.ViewMsgAddChannel(sourceStorage, sourceKinds, pendingSources),
+		),
+	)
+
+	app := supervisor.New(
+		supervisor.Policy{
+			InitialBackoff:  cfg.Supervisor.RestartInitialBackoff,
+			MaxBackoff:      cfg.Supervisor.RestartMaxBackoff,
+			MaxRestarts:     cfg.Supervisor.MaxRestarts,
+			RestartWindow:   cfg.Supervisor.RestartWindow,
+			ShutdownTimeout: cfg.Supervisor.ShutdownTimeout,
+		},
+		log,
+	)
+	app.Add("fetcher", f.Start)
+	app.Add("notifier", n.Start)
+	app.Add("digest", digestPublisher.Start)
+	app.Add("subscriptions", deliverer.Start)
+	app.Add("bot", newsBot.Run)
+
+	if err := app.Run(ctx); err != nil {
+		log.Error("application stopped with error", "err", err)
+		os.Exit(1)
 	}
 }
 
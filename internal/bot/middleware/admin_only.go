@@ -11,26 +11,17 @@ func AdminOnly(channelID int64, next botkit.ViewFunc) botkit.ViewFunc {
 	const op = "bot.middleware.AdminOnly"
 
 	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
-		admins, err := bot.GetChatAdministrators(
-			tgbotapi.ChatAdministratorsConfig{
-				ChatConfig: tgbotapi.ChatConfig{
-					ChatID: channelID,
-				},
-			},
-		)
-
+		admin, err := isAdmin(bot, channelID, update.SentFrom().ID)
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		for _, admin := range admins {
-			if admin.User.ID == update.Message.From.ID {
-				return next(ctx, bot, update)
-			}
+		if admin {
+			return next(ctx, bot, update)
 		}
 
 		if _, err := bot.Send(tgbotapi.NewMessage(
-			update.Message.Chat.ID,
+			update.FromChat().ID,
 			"You have no permissions"),
 		); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
@@ -39,3 +30,43 @@ func AdminOnly(channelID int64, next botkit.ViewFunc) botkit.ViewFunc {
 		return nil
 	}
 }
+
+// AdminOnlyQuiet is AdminOnly for views matched on ordinary messages, which
+// anyone may send: messages from others are ignored without a reply.
+func AdminOnlyQuiet(channelID int64, next botkit.ViewFunc) botkit.ViewFunc {
+	const op = "bot.middleware.AdminOnlyQuiet"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		admin, err := isAdmin(bot, channelID, update.SentFrom().ID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !admin {
+			return nil
+		}
+
+		return next(ctx, bot, update)
+	}
+}
+
+func isAdmin(bot *tgbotapi.BotAPI, channelID int64, userID int64) (bool, error) {
+	admins, err := bot.GetChatAdministrators(
+		tgbotapi.ChatAdministratorsConfig{
+			ChatConfig: tgbotapi.ChatConfig{
+				ChatID: channelID,
+			},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	for _, admin := range admins {
+		if admin.User.ID == userID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+)
+
+// PrivateOnly runs next for messages in a private chat with the bot only.
+func PrivateOnly(next botkit.ViewFunc) botkit.ViewFunc {
+	const op = "bot.middleware.PrivateOnly"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		if update.FromChat().IsPrivate() {
+			return next(ctx, bot, update)
+		}
+
+		if _, err := bot.Send(tgbotapi.NewMessage(
+			update.FromChat().ID,
+			"This command works in a private chat with the bot"),
+		); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"news-feed-bot/internal/model"
+	"sync"
+	"time"
+)
+
+// pendingTTL is how long an admin has to press an inline button.
+const pendingTTL = 15 * time.Minute
+
+// PendingSources keeps sources offered to an admin with inline buttons
+// until one of them is picked. Tokens are short enough for callback data.
+type PendingSources struct {
+	mu      sync.Mutex
+	entries map[string]pendingSources
+}
+
+type pendingSources struct {
+	sources []model.Source
+	expires time.Time
+}
+
+func NewPendingSources() *PendingSources {
+	return &PendingSources{entries: make(map[string]pendingSources)}
+}
+
+// Put stores sources and returns the token to look them up with.
+func (p *PendingSources) Put(sources []model.Source) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+
+	for token, entry := range p.entries {
+		if now.After(entry.expires) {
+			delete(p.entries, token)
+		}
+	}
+
+	token := newToken()
+	p.entries[token] = pendingSources{sources: sources, expires: now.Add(pendingTTL)}
+
+	return token
+}
+
+// Take removes and returns the sources stored under token.
+func (p *PendingSources) Take(token string) ([]model.Source, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.entries[token]
+	if !ok {
+		return nil, false
+	}
+
+	delete(p.entries, token)
+
+	if time.Now().After(entry.expires) {
+		return nil, false
+	}
+
+	return entry.sources, true
+}
+
+func newToken() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
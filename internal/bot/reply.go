@@ -0,0 +1,80 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"strings"
+)
+
+// replyText sends a plain text message to the chat the update came from.
+func replyText(bot *tgbotapi.BotAPI, update tgbotapi.Update, text string) error {
+	_, err := bot.Send(tgbotapi.NewMessage(update.FromChat().ID, text))
+	return err
+}
+
+// editCallbackMessage replaces the text of the message whose inline button
+// was pressed, removing its keyboard.
+func editCallbackMessage(bot *tgbotapi.BotAPI, update tgbotapi.Update, text string, parseMode string) error {
+	msg := update.CallbackQuery.Message
+
+	edit := tgbotapi.NewEditMessageText(msg.Chat.ID, msg.MessageID, text)
+	edit.ParseMode = parseMode
+
+	_, err := bot.Send(edit)
+	return err
+}
+
+// maxMessageLen is the Telegram limit for the text of a single message.
+const maxMessageLen = 4096
+
+// replyLines sends lines as plain text, splitting them over as many
+// messages as needed to stay within the message length limit.
+func replyLines(bot *tgbotapi.BotAPI, update tgbotapi.Update, lines []string) error {
+	for _, chunk := range chunkLines(lines, maxMessageLen) {
+		if err := replyText(bot, update, chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chunkLines joins lines with newlines into chunks of at most limit bytes,
+// which keeps them within Telegram's character limit too. A single line
+// longer than limit is cut.
+func chunkLines(lines []string, limit int) []string {
+	var (
+		chunks []string
+		sb     strings.Builder
+	)
+
+	for _, line := range lines {
+		if len(line) > limit {
+			line = strings.ToValidUTF8(line[:limit], "")
+		}
+
+		if sb.Len() > 0 && sb.Len()+1+len(line) > limit {
+			chunks = append(chunks, sb.String())
+			sb.Reset()
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(line)
+	}
+
+	if sb.Len() > 0 {
+		chunks = append(chunks, sb.String())
+	}
+
+	return chunks
+}
+
+// truncateMessage cuts text to fit into a single message.
+func truncateMessage(text string) string {
+	if len(text) <= maxMessageLen {
+		return text
+	}
+
+	return strings.ToValidUTF8(text[:maxMessageLen-len("…")], "") + "…"
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+// searchQueryTTL is how long the page buttons of a search keep working.
+const searchQueryTTL = time.Hour
+
+// SearchQueries remembers search queries behind short tokens, as queries may
+// not fit into the 64 bytes of callback data of the page buttons.
+type SearchQueries struct {
+	mu      sync.Mutex
+	entries map[string]searchQuery
+}
+
+type searchQuery struct {
+	query   string
+	expires time.Time
+}
+
+func NewSearchQueries() *SearchQueries {
+	return &SearchQueries{entries: make(map[string]searchQuery)}
+}
+
+// Put stores query and returns the token to look it up with.
+func (q *SearchQueries) Put(query string) string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+
+	for token, entry := range q.entries {
+		if now.After(entry.expires) {
+			delete(q.entries, token)
+		}
+	}
+
+	token := newToken()
+	q.entries[token] = searchQuery{query: query, expires: now.Add(searchQueryTTL)}
+
+	return token
+}
+
+// Get returns the query stored under token. Unlike PendingSources, a query
+// stays available for further pages until it expires.
+func (q *SearchQueries) Get(token string) (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entry, ok := q.entries[token]
+	if !ok || time.Now().After(entry.expires) {
+		return "", false
+	}
+
+	return entry.query, true
+}
@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/model"
+	"strings"
+)
+
+type ChannelAdder interface {
+	AddChannel(ctx context.Context, channel model.Channel) (int64, error)
+}
+
+// ViewCmdAddChannel adds a channel to publish articles to. The bot has to
+// be an admin of the channel; its title is the default name.
+func ViewCmdAddChannel(adder ChannelAdder) botkit.ViewFunc {
+	const op = "bot.ViewCmdAddChannel"
+
+	type addChannelArgs struct {
+		ChatID int64  `json:"chat_id"`
+		Name   string `json:"name"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[addChannelArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		chat, err := bot.GetChat(tgbotapi.ChatInfoConfig{
+			ChatConfig: tgbotapi.ChatConfig{ChatID: args.ChatID},
+		})
+		if err != nil {
+			if err := replyText(bot, update, fmt.Sprintf("Cannot access chat %d: %s", args.ChatID, err)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		channel := model.Channel{
+			ChatID: args.ChatID,
+			Name:   strings.TrimSpace(args.Name),
+		}
+		if channel.Name == "" {
+			channel.Name = chat.Title
+		}
+
+		channel.ID, err = adder.AddChannel(ctx, channel)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		msgText := fmt.Sprintf(
+			"Channel #%d %s was added. It receives all articles until it has a route, see /addroute.",
+			channel.ID,
+			channel.Name,
+		)
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
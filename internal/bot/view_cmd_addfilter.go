@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/filter"
+	"news-feed-bot/internal/model"
+	"strings"
+)
+
+type FilterRuleAdder interface {
+	AddRule(ctx context.Context, rule model.FilterRule) (int64, error)
+}
+
+// filterRuleArgs describes a rule in the arguments of /addfilter and /testfilter.
+type filterRuleArgs struct {
+	Action   string `json:"action"`
+	Field    string `json:"field"`
+	Match    string `json:"match"`
+	Pattern  string `json:"pattern"`
+	SourceID int64  `json:"source_id"`
+}
+
+func (a filterRuleArgs) rule() model.FilterRule {
+	rule := model.FilterRule{
+		Action:   a.Action,
+		Field:    a.Field,
+		Match:    a.Match,
+		Pattern:  a.Pattern,
+		SourceID: a.SourceID,
+	}
+
+	// Store the defaults Compile would apply so listings show them.
+	if rule.Match == "" {
+		rule.Match = filter.MatchContains
+	}
+	if rule.Field == "" {
+		rule.Field = filter.FieldAny
+	}
+
+	return rule
+}
+
+// ViewCmdAddFilter stores a filter rule. The fetcher picks it up on its next run.
+func ViewCmdAddFilter(storage FilterRuleAdder) botkit.ViewFunc {
+	const op = "bot.ViewCmdAddFilter"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[filterRuleArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		rule := args.rule()
+
+		if _, err := filter.Compile(rule); err != nil {
+			if !errors.Is(err, filter.ErrInvalidRule) {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if err := replyText(bot, update, invalidRuleText(err)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		rule.ID, err = storage.AddRule(ctx, rule)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := replyText(bot, update, "Filter rule was added: "+filter.Describe(rule)); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+func invalidRuleText(err error) string {
+	return fmt.Sprintf(
+		"Invalid rule: %s\n\nActions: %s\nFields: %s\nMatches: %s",
+		err,
+		strings.Join(filter.Actions, ", "),
+		strings.Join(filter.Fields, ", "),
+		strings.Join(filter.Matches, ", "),
+	)
+}
@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/filter"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/routing"
+	"strings"
+)
+
+type ChannelRouteAdder interface {
+	Channels(ctx context.Context) ([]model.Channel, error)
+	AddRoute(ctx context.Context, route model.ChannelRoute) (int64, error)
+}
+
+// ViewCmdAddRoute sends a source, a source group or the articles matching a
+// filter pattern to a channel. Once a channel has routes it only receives
+// the articles matching one of them.
+func ViewCmdAddRoute(storage ChannelRouteAdder) botkit.ViewFunc {
+	const op = "bot.ViewCmdAddRoute"
+
+	type addRouteArgs struct {
+		ChannelID int64  `json:"channel"`
+		SourceID  int64  `json:"source_id"`
+		Group     string `json:"group"`
+		Field     string `json:"field"`
+		Match     string `json:"match"`
+		Pattern   string `json:"pattern"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[addRouteArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		channels, err := storage.Channels(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if _, ok := findChannel(channels, args.ChannelID); !ok || args.ChannelID == 0 {
+			if err := replyText(bot, update, fmt.Sprintf("There is no channel #%d. See /channels.", args.ChannelID)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		route := model.ChannelRoute{
+			ChannelID: args.ChannelID,
+			SourceID:  args.SourceID,
+			Group:     strings.TrimSpace(args.Group),
+			Field:     args.Field,
+			Match:     args.Match,
+			Pattern:   args.Pattern,
+		}
+
+		// Store the defaults the filter applies so listings show them.
+		if route.Pattern != "" {
+			if route.Match == "" {
+				route.Match = filter.MatchContains
+			}
+			if route.Field == "" {
+				route.Field = filter.FieldAny
+			}
+		}
+
+		if _, err := routing.Compile(route); err != nil {
+			if !errors.Is(err, routing.ErrInvalidRoute) {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if err := replyText(bot, update, invalidRouteText(err)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		route.ID, err = storage.AddRoute(ctx, route)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		msgText := fmt.Sprintf("Route was added to channel #%d: %s", route.ChannelID, routing.Describe(route))
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+func invalidRouteText(err error) string {
+	return fmt.Sprintf(
+		"Invalid route: %s\n\nSet one of source_id, group or pattern.\nFields: %s\nMatches: %s",
+		err,
+		strings.Join(filter.Fields, ", "),
+		strings.Join(filter.Matches, ", "),
+	)
+}
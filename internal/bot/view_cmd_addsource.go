@@ -6,19 +6,55 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"news-feed-bot/internal/botkit"
 	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/source"
+	"news-feed-bot/internal/urlutil"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// CallbackAddSource prefixes callback data of the feed choice buttons.
+const CallbackAddSource = "addsource"
+
+// previewItems is how many of the latest items are shown before confirming.
+const previewItems = 5
+
 type SourceStorage interface {
+	Sources(ctx context.Context) ([]model.Source, error)
 	Add(ctx context.Context, source model.Source) (int64, error)
 }
 
-func ViewCmdAddSource(storage SourceStorage) botkit.ViewFunc {
+type SourceKinds interface {
+	Has(kind string) bool
+	Kinds() []string
+	New(src model.Source) (source.Source, error)
+	Discover(ctx context.Context, src model.Source) ([]source.Candidate, error)
+}
+
+// titledSource is implemented by sources that know the title of the
+// feed or page they fetched.
+type titledSource interface {
+	FeedTitle() string
+}
+
+// ViewCmdAddSource adds a source. For feed kinds, or when no kind is given,
+// the URL may point at a website: its feeds are discovered and, if there is
+// more than one, offered to the admin as inline buttons. The chosen source
+// is fetched and previewed, and only stored once the admin confirms it.
+func ViewCmdAddSource(storage SourceStorage, kinds SourceKinds, pending *PendingSources) botkit.ViewFunc {
 	const op = "bot.ViewCmdAddSource"
 
 	type addSourceArgs struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
+		Name      string                 `json:"name"`
+		URL       string                 `json:"url"`
+		Kind      string                 `json:"kind"`
+		Selectors *model.ScrapeSelectors `json:"selectors"`
+		HTTP      *model.HTTPSettings    `json:"http"`
+		// Interval is a duration such as "30m", or "auto".
+		Interval string `json:"interval"`
+		// Languages lists the ISO 639-1 codes of the items to keep.
+		Languages []string `json:"languages"`
 	}
 
 	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
@@ -27,25 +63,103 @@ func ViewCmdAddSource(storage SourceStorage) botkit.ViewFunc {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		source := model.Source{
-			Name:      args.Name,
-			FeedURL:   args.URL,
-			CreatedAt: time.Now().UTC(),
+		if args.Kind != "" && !kinds.Has(args.Kind) {
+			if err := replyText(bot, update, fmt.Sprintf(
+				"Unknown source kind %q. Available kinds: %s",
+				args.Kind,
+				strings.Join(kinds.Kinds(), ", "),
+			)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
 		}
 
-		sourceID, err := storage.Add(ctx, source)
+		interval, auto, err := parseFetchInterval(args.Interval)
+		if err != nil {
+			if err := replyText(bot, update, "Invalid interval: "+err.Error()); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		src := model.Source{
+			Name:          args.Name,
+			FeedURL:       args.URL,
+			Kind:          args.Kind,
+			Selectors:     args.Selectors,
+			HTTP:          args.HTTP,
+			FetchInterval: interval,
+			AutoInterval:  auto,
+			Languages:     normalizeLanguages(args.Languages),
+			CreatedAt:     time.Now().UTC(),
+		}
+
+		existing, err := storage.Sources(ctx)
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
+		if dup, ok := findDuplicate(existing, src.FeedURL); ok {
+			if err := replyText(bot, update, duplicateText(dup)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
 		var (
-			msgText = fmt.Sprintf(
-				"Source was added with id: `%d`\\."+
-					" Use this id for managing this source\\.", sourceID)
-			reply = tgbotapi.NewMessage(update.Message.Chat.ID, msgText)
+			choices    []model.Source
+			duplicates []string
 		)
 
-		reply.ParseMode = "MarkdownV2"
+		if src.Kind != "" && !source.IsFeedKind(src.Kind) {
+			choices = []model.Source{src}
+		} else {
+			candidates, err := kinds.Discover(ctx, src)
+			if err != nil {
+				if err := replyText(bot, update, "No feed found: "+err.Error()); err != nil {
+					return fmt.Errorf("%s: %w", op, err)
+				}
+
+				return nil
+			}
+
+			for _, candidate := range candidates {
+				if dup, ok := findDuplicate(existing, candidate.URL); ok {
+					duplicates = append(duplicates, duplicateText(dup))
+					continue
+				}
+
+				choices = append(choices, withCandidate(src, candidate))
+			}
+		}
+
+		// Every feed found is registered already.
+		if len(choices) == 0 {
+			if err := replyLines(bot, update, duplicates); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		var (
+			text   string
+			markup tgbotapi.InlineKeyboardMarkup
+		)
+
+		if len(choices) == 1 {
+			text, markup = previewSource(ctx, kinds, pending, choices[0])
+		} else {
+			text, markup = "Several feeds were found, choose one:", feedChoiceKeyboard(pending.Put(choices), choices)
+		}
+
+		reply := tgbotapi.NewMessage(update.Message.Chat.ID, text)
+		if len(markup.InlineKeyboard) > 0 {
+			reply.ReplyMarkup = markup
+		}
 
 		if _, err := bot.Send(reply); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
@@ -54,3 +168,238 @@ func ViewCmdAddSource(storage SourceStorage) botkit.ViewFunc {
 		return nil
 	}
 }
+
+// ViewCallbackAddSource handles the buttons sent by ViewCmdAddSource: picking
+// one of several discovered feeds previews it, confirming a preview stores it.
+func ViewCallbackAddSource(storage SourceStorage, kinds SourceKinds, pending *PendingSources) botkit.ViewFunc {
+	const op = "bot.ViewCallbackAddSource"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		payload := botkit.CallbackPayload(update)
+		if len(payload) != 2 {
+			return fmt.Errorf("%s: malformed callback data %q", op, update.CallbackData())
+		}
+
+		choices, ok := pending.Take(payload[0])
+		if !ok {
+			if err := editCallbackMessage(bot, update, "This choice has expired, run /addsource again.", ""); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		var chosen model.Source
+
+		switch payload[1] {
+		case "cancel":
+			if err := editCallbackMessage(bot, update, "Adding the source was cancelled.", ""); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		case "confirm":
+			chosen = choices[0]
+		default:
+			i, err := strconv.Atoi(payload[1])
+			if err != nil || i < 0 || i >= len(choices) {
+				return fmt.Errorf("%s: malformed callback data %q", op, update.CallbackData())
+			}
+
+			chosen = choices[i]
+		}
+
+		// The source list may have changed while the buttons were shown.
+		existing, err := storage.Sources(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if dup, ok := findDuplicate(existing, chosen.FeedURL); ok {
+			if err := editCallbackMessage(bot, update, duplicateText(dup), ""); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		if payload[1] != "confirm" {
+			text, markup := previewSource(ctx, kinds, pending, chosen)
+			msg := update.CallbackQuery.Message
+
+			edit := tgbotapi.NewEditMessageText(msg.Chat.ID, msg.MessageID, text)
+			if len(markup.InlineKeyboard) > 0 {
+				edit.ReplyMarkup = &markup
+			}
+
+			if _, err := bot.Send(edit); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		sourceID, err := storage.Add(ctx, chosen)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := editCallbackMessage(bot, update, sourceAddedText(sourceID), "MarkdownV2"); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+// previewSource fetches src through its source kind and describes the result
+// together with Confirm/Cancel buttons. Sources that fail to build or fetch
+// get an explanation and no buttons.
+func previewSource(
+	ctx context.Context,
+	kinds SourceKinds,
+	pending *PendingSources,
+	src model.Source,
+) (string, tgbotapi.InlineKeyboardMarkup) {
+	feed, err := kinds.New(src)
+	if err != nil {
+		return "Invalid source: " + err.Error(), tgbotapi.InlineKeyboardMarkup{}
+	}
+
+	items, err := feed.Fetch(ctx)
+	if err != nil {
+		return "The source could not be fetched: " + err.Error(), tgbotapi.InlineKeyboardMarkup{}
+	}
+
+	var title string
+	if titled, ok := feed.(titledSource); ok {
+		title = titled.FeedTitle()
+	}
+
+	if src.Name == "" {
+		src.Name = title
+	}
+
+	lines := []string{
+		"Name: " + src.Name,
+		"Title: " + title,
+		"Kind: " + src.Kind,
+		"URL: " + src.FeedURL,
+		"",
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Date.After(items[j].Date) })
+
+	if len(items) == 0 {
+		lines = append(lines, "The source has no items yet.")
+	} else {
+		lines = append(lines, fmt.Sprintf("Latest items (%d in total):", len(items)))
+	}
+
+	for i, item := range items {
+		if i == previewItems {
+			break
+		}
+
+		line := fmt.Sprintf("%d. %s", i+1, item.Title)
+		if !item.Date.IsZero() {
+			line += " (" + item.Date.UTC().Format(time.DateTime) + ")"
+		}
+
+		lines = append(lines, line)
+	}
+
+	token := pending.Put([]model.Source{src})
+
+	markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Confirm", botkit.CallbackData(CallbackAddSource, token, "confirm")),
+		tgbotapi.NewInlineKeyboardButtonData("Cancel", botkit.CallbackData(CallbackAddSource, token, "cancel")),
+	))
+
+	return truncateMessage(strings.Join(lines, "\n")), markup
+}
+
+// findDuplicate looks for a source registered under the same normalized URL.
+func findDuplicate(existing []model.Source, feedURL string) (model.Source, bool) {
+	normalized := urlutil.Normalize(feedURL)
+
+	for _, src := range existing {
+		if urlutil.Normalize(src.FeedURL) == normalized {
+			return src, true
+		}
+	}
+
+	return model.Source{}, false
+}
+
+func duplicateText(dup model.Source) string {
+	return fmt.Sprintf("This feed is already registered as %q with id %d.", dup.Name, dup.ID)
+}
+
+func sourceAddedText(sourceID int64) string {
+	return fmt.Sprintf(
+		"Source was added with id: `%d`\\."+
+			" Use this id for managing this source\\.", sourceID)
+}
+
+// withCandidate points src at a discovered feed. The feed title is used
+// when the admin did not name the source.
+func withCandidate(src model.Source, candidate source.Candidate) model.Source {
+	src.FeedURL = candidate.URL
+	src.Kind = candidate.Kind
+
+	if src.Name == "" {
+		src.Name = candidate.Title
+	}
+
+	return src
+}
+
+func feedChoiceKeyboard(token string, choices []model.Source) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	for i, choice := range choices {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("%s (%s)", choice.FeedURL, choice.Kind),
+				botkit.CallbackData(CallbackAddSource, token, strconv.Itoa(i)),
+			),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(
+			"Cancel",
+			botkit.CallbackData(CallbackAddSource, token, "cancel"),
+		),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// parseFetchInterval accepts an empty value for the default interval,
+// "auto" for adaptive scheduling or a Go duration in whole seconds, since
+// intervals are stored with second precision.
+func parseFetchInterval(raw string) (time.Duration, bool, error) {
+	switch raw {
+	case "":
+		return 0, false, nil
+	case "auto":
+		return 0, true, nil
+	}
+
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if interval < time.Second {
+		return 0, false, fmt.Errorf("interval must be at least 1s")
+	}
+
+	if interval%time.Second != 0 {
+		return 0, false, fmt.Errorf("interval must be a whole number of seconds")
+	}
+
+	return interval, false, nil
+}
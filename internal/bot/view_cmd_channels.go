@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/digest"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/routing"
+)
+
+type ChannelRouteLister interface {
+	Channels(ctx context.Context) ([]model.Channel, error)
+	Routes(ctx context.Context) ([]model.ChannelRoute, error)
+}
+
+// ViewCmdChannels lists the channels articles are published to along with
+// their routes.
+func ViewCmdChannels(lister ChannelRouteLister) botkit.ViewFunc {
+	const op = "bot.ViewCmdChannels"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		channels, err := lister.Channels(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		routes, err := lister.Routes(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		byChannel := make(map[int64][]model.ChannelRoute)
+		for _, route := range routes {
+			byChannel[route.ChannelID] = append(byChannel[route.ChannelID], route)
+		}
+
+		lines := []string{fmt.Sprintf("Channels (total %d):", len(channels))}
+
+		for _, channel := range channels {
+			lines = append(lines,
+				"",
+				fmt.Sprintf("#%d %s (chat %d)", channel.ID, channel.Name, channel.ChatID),
+				digest.Describe(channel.Digest),
+			)
+
+			if len(byChannel[channel.ID]) == 0 {
+				lines = append(lines, "all articles")
+				continue
+			}
+
+			for _, route := range byChannel[channel.ID] {
+				lines = append(lines, routing.Describe(route))
+			}
+		}
+
+		if err := replyLines(bot, update, lines); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+// findChannel returns the channel with the id, or the first channel for a
+// zero id.
+func findChannel(channels []model.Channel, id int64) (model.Channel, bool) {
+	for _, channel := range channels {
+		if id == 0 || channel.ID == id {
+			return channel, true
+		}
+	}
+
+	return model.Channel{}, false
+}
@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/model"
+)
+
+type ChannelRemover interface {
+	Channels(ctx context.Context) ([]model.Channel, error)
+	// DeleteChannel reports whether the channel existed.
+	DeleteChannel(ctx context.Context, id int64) (bool, error)
+}
+
+// ViewCmdDeleteChannel stops publishing to a channel. The channel from the
+// config cannot be deleted as it is added back on start.
+func ViewCmdDeleteChannel(remover ChannelRemover, defaultChatID int64) botkit.ViewFunc {
+	const op = "bot.ViewCmdDeleteChannel"
+
+	type deleteChannelArgs struct {
+		ID int64 `json:"id"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[deleteChannelArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		channels, err := remover.Channels(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		msgText := fmt.Sprintf("Channel #%d was deleted.", args.ID)
+
+		channel, ok := findChannel(channels, args.ID)
+		switch {
+		case !ok || args.ID == 0:
+			msgText = fmt.Sprintf("There is no channel #%d.", args.ID)
+		case channel.ChatID == defaultChatID:
+			msgText = "The channel from the config cannot be deleted. Add routes to narrow it down instead."
+		default:
+			if _, err := remover.DeleteChannel(ctx, args.ID); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+)
+
+type FilterRuleRemover interface {
+	// DeleteRule reports whether the rule existed.
+	DeleteRule(ctx context.Context, id int64) (bool, error)
+}
+
+func ViewCmdDeleteFilter(remover FilterRuleRemover) botkit.ViewFunc {
+	const op = "bot.ViewCmdDeleteFilter"
+
+	type deleteFilterArgs struct {
+		ID int64 `json:"id"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[deleteFilterArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		deleted, err := remover.DeleteRule(ctx, args.ID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		msgText := fmt.Sprintf("Filter rule #%d was deleted.", args.ID)
+		if !deleted {
+			msgText = fmt.Sprintf("There is no filter rule #%d.", args.ID)
+		}
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
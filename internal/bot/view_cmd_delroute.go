@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+)
+
+type ChannelRouteRemover interface {
+	// DeleteRoute reports whether the route existed.
+	DeleteRoute(ctx context.Context, id int64) (bool, error)
+}
+
+func ViewCmdDeleteRoute(remover ChannelRouteRemover) botkit.ViewFunc {
+	const op = "bot.ViewCmdDeleteRoute"
+
+	type deleteRouteArgs struct {
+		ID int64 `json:"id"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[deleteRouteArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		deleted, err := remover.DeleteRoute(ctx, args.ID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		msgText := fmt.Sprintf("Route #%d was deleted.", args.ID)
+		if !deleted {
+			msgText = fmt.Sprintf("There is no route #%d.", args.ID)
+		}
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,112 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"net/url"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/opml"
+)
+
+// ViewCmdExportSources sends all sources back as an OPML document. Scraper
+// selectors and HTTP settings are kept in extension attributes so the
+// document can be imported back without losing them. Credentials are left
+// out unless asked for with {"credentials": true} in a private chat.
+func ViewCmdExportSources(lister SourceLister) botkit.ViewFunc {
+	const op = "bot.ViewCmdExportSources"
+
+	type exportSourcesArgs struct {
+		Credentials bool `json:"credentials"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		var args exportSourcesArgs
+
+		if rawArgs := update.Message.CommandArguments(); rawArgs != "" {
+			parsed, err := botkit.ParseJSON[exportSourcesArgs](rawArgs)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			args = parsed
+		}
+
+		if args.Credentials && !update.Message.Chat.IsPrivate() {
+			if err := replyText(bot, update, "Credentials are exported in a private chat with the bot only."); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		sources, err := lister.Sources(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		feeds := make([]opml.Feed, 0, len(sources))
+		for _, source := range sources {
+			settings := source.HTTP
+			if !args.Credentials {
+				settings = withoutCredentials(settings)
+			}
+
+			feeds = append(feeds, opml.Feed{
+				Title:     source.Name,
+				Type:      source.Kind,
+				URL:       source.FeedURL,
+				Selectors: source.Selectors,
+				HTTP:      settings,
+			})
+		}
+
+		var buf bytes.Buffer
+
+		if err := opml.Write(&buf, "News feed bot sources", feeds); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		doc := tgbotapi.NewDocument(update.Message.Chat.ID, tgbotapi.FileBytes{
+			Name:  "sources.opml",
+			Bytes: buf.Bytes(),
+		})
+		doc.Caption = fmt.Sprintf("%d sources", len(feeds))
+		if !args.Credentials {
+			doc.Caption += ", credentials left out"
+		}
+
+		if _, err := bot.Send(doc); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+// withoutCredentials copies settings without the password, bearer token,
+// custom headers and proxy user info, any of which may hold a secret.
+func withoutCredentials(settings *model.HTTPSettings) *model.HTTPSettings {
+	if settings == nil {
+		return nil
+	}
+
+	redacted := *settings
+	redacted.BasicPassword = ""
+	redacted.BearerToken = ""
+	redacted.Headers = nil
+
+	if redacted.ProxyURL != "" {
+		proxy, err := url.Parse(redacted.ProxyURL)
+		if err != nil {
+			redacted.ProxyURL = ""
+		} else if proxy.User != nil {
+			proxy.User = nil
+			redacted.ProxyURL = proxy.String()
+		}
+	}
+
+	return &redacted
+}
@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/filter"
+	"news-feed-bot/internal/model"
+)
+
+type FilterRuleLister interface {
+	Rules(ctx context.Context) ([]model.FilterRule, error)
+}
+
+// ViewCmdFilters lists the stored filter rules. Keywords from the config
+// are not listed as they cannot be changed from the bot.
+func ViewCmdFilters(lister FilterRuleLister) botkit.ViewFunc {
+	const op = "bot.ViewCmdFilters"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		rules, err := lister.Rules(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(rules) == 0 {
+			if err := replyText(bot, update, "There are no filter rules. Add one with /addfilter."); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		lines := []string{fmt.Sprintf("Filter rules (total %d):", len(rules)), ""}
+
+		for _, rule := range rules {
+			lines = append(lines, filter.Describe(rule))
+		}
+
+		if err := replyLines(bot, update, lines); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
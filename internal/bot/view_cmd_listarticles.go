@@ -61,9 +61,15 @@ func ViewCmdListArticles(lister ArticleLister) botkit.ViewFunc {
 }
 
 func formatArticle(article model.Article) string {
+	language := article.Language
+	if language == "" {
+		language = "unknown"
+	}
+
 	return fmt.Sprintf(
-		"⚪ *[%s](%s)",
+		"⚪ *[%s](%s)*\nLanguage: %s",
 		markup.EscapeForMarkdown(article.Title),
 		markup.EscapeForMarkdown(article.Link),
+		markup.EscapeForMarkdown(language),
 	)
 }
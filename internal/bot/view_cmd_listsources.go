@@ -48,9 +48,35 @@ func ViewCmdListSources(lister SourceLister) botkit.ViewFunc {
 
 func formatSource(source model.Source) string {
 	return fmt.Sprintf(
-		"⚪ *%s*\nID: `%d`\nFeed URL: %s",
+		"⚪ *%s*\nID: `%d`\nKind: %s\nInterval: %s\nLanguages: %s\nPriority: %s\nGroup: %s\nFeed URL: %s",
 		markup.EscapeForMarkdown(source.Name),
 		source.ID,
+		markup.EscapeForMarkdown(source.Kind),
+		markup.EscapeForMarkdown(formatFetchInterval(source)),
+		markup.EscapeForMarkdown(formatLanguages(source.Languages)),
+		markup.EscapeForMarkdown(fmt.Sprintf("%g", source.Priority)),
+		markup.EscapeForMarkdown(formatGroup(source.Group)),
 		markup.EscapeForMarkdown(source.FeedURL),
 	)
 }
+
+func formatFetchInterval(source model.Source) string {
+	switch {
+	case source.AutoInterval && source.FetchInterval > 0:
+		return fmt.Sprintf("auto (currently %s)", source.FetchInterval)
+	case source.AutoInterval:
+		return "auto"
+	case source.FetchInterval > 0:
+		return source.FetchInterval.String()
+	}
+
+	return "default"
+}
+
+func formatGroup(group string) string {
+	if group == "" {
+		return "none"
+	}
+
+	return group
+}
@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/scoring"
+	"time"
+)
+
+const (
+	defaultScoresLimit = 10
+	maxScoresLimit     = 50
+)
+
+type ArticleRanker interface {
+	Rank(ctx context.Context, channelID int64, now time.Time) ([]scoring.Scored, error)
+}
+
+type ChannelLister interface {
+	Channels(ctx context.Context) ([]model.Channel, error)
+}
+
+// ViewCmdScores shows the articles waiting for a channel in the order the
+// notifier would post them, with the breakdown of each score. {"limit": n}
+// shows more or fewer than the default and {"channel": id} picks a channel
+// other than the first one.
+func ViewCmdScores(ranker ArticleRanker, channels ChannelLister) botkit.ViewFunc {
+	const op = "bot.ViewCmdScores"
+
+	type scoresArgs struct {
+		Limit     int   `json:"limit"`
+		ChannelID int64 `json:"channel"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		var args scoresArgs
+
+		if rawArgs := update.Message.CommandArguments(); rawArgs != "" {
+			parsed, err := botkit.ParseJSON[scoresArgs](rawArgs)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			args = parsed
+		}
+
+		limit := args.Limit
+		if limit <= 0 {
+			limit = defaultScoresLimit
+		}
+		limit = min(limit, maxScoresLimit)
+
+		list, err := channels.Channels(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		channel, ok := findChannel(list, args.ChannelID)
+		if !ok {
+			if err := replyText(bot, update, "There is no such channel. See /channels."); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		ranked, err := ranker.Rank(ctx, channel.ID, time.Now())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(ranked) == 0 {
+			if err := replyText(bot, update, "There are no articles waiting to be posted to "+channel.Name+"."); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		lines := []string{
+			fmt.Sprintf("Top %d of %d candidates for %s:", min(limit, len(ranked)), len(ranked), channel.Name),
+		}
+
+		for i, scored := range ranked[:min(limit, len(ranked))] {
+			lines = append(lines, "", formatScored(i+1, scored))
+		}
+
+		if err := replyLines(bot, update, lines); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+func formatScored(n int, scored scoring.Scored) string {
+	sourceName := scored.Source.Name
+	if sourceName == "" {
+		sourceName = fmt.Sprintf("source %d", scored.Source.ID)
+	}
+
+	lastPosted := "not posted recently"
+	if !scored.Breakdown.SourceLastPosted.IsZero() {
+		lastPosted = "last posted " + time.Since(scored.Breakdown.SourceLastPosted).Round(time.Minute).String() + " ago"
+	}
+
+	return fmt.Sprintf(
+		"%d. %s\n%s · published %s · %s\nscore %s",
+		n,
+		scored.Article.Title,
+		sourceName,
+		scored.Article.PublishedAt.Format("2006-01-02 15:04"),
+		lastPosted,
+		scored.Breakdown,
+	)
+}
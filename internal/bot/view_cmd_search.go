@@ -0,0 +1,170 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/model"
+	"strconv"
+	"strings"
+)
+
+// CallbackSearch prefixes callback data of the search page buttons.
+const CallbackSearch = "search"
+
+// searchPageSize is how many results are shown per page.
+const searchPageSize = 5
+
+type ArticleSearcher interface {
+	SearchArticles(ctx context.Context, query string, limit uint64, offset uint64) ([]model.Article, int, error)
+}
+
+// ViewCmdSearch runs a full-text search over the stored articles. The query
+// is the plain text after the command and supports quoted phrases, "or" and
+// -excluded words.
+func ViewCmdSearch(searcher ArticleSearcher, queries *SearchQueries) botkit.ViewFunc {
+	const op = "bot.ViewCmdSearch"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		query := strings.TrimSpace(update.Message.CommandArguments())
+		if query == "" {
+			if err := replyText(bot, update, "Usage: /search <query>"); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		text, keyboard, err := searchPage(ctx, searcher, queries.Put(query), query, 0)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		reply := tgbotapi.NewMessage(update.Message.Chat.ID, text)
+		reply.DisableWebPagePreview = true
+		if keyboard != nil {
+			reply.ReplyMarkup = *keyboard
+		}
+
+		if _, err := bot.Send(reply); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+// ViewCallbackSearch shows another page of a search. The payload is
+// the query token and the page number.
+func ViewCallbackSearch(searcher ArticleSearcher, queries *SearchQueries) botkit.ViewFunc {
+	const op = "bot.ViewCallbackSearch"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		payload := botkit.CallbackPayload(update)
+		if len(payload) != 2 {
+			return fmt.Errorf("%s: malformed callback data %q", op, update.CallbackData())
+		}
+
+		page, err := strconv.Atoi(payload[1])
+		if err != nil || page < 0 {
+			return fmt.Errorf("%s: malformed callback data %q", op, update.CallbackData())
+		}
+
+		query, ok := queries.Get(payload[0])
+		if !ok {
+			if err := editCallbackMessage(bot, update, "This search has expired, run /search again.", ""); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		text, keyboard, err := searchPage(ctx, searcher, payload[0], query, page)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		msg := update.CallbackQuery.Message
+
+		edit := tgbotapi.NewEditMessageText(msg.Chat.ID, msg.MessageID, text)
+		edit.DisableWebPagePreview = true
+		edit.ReplyMarkup = keyboard
+
+		if _, err := bot.Send(edit); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+// searchPage renders one page of results with buttons for the neighbouring
+// pages. The keyboard is nil when everything fits on one page.
+func searchPage(
+	ctx context.Context,
+	searcher ArticleSearcher,
+	token string,
+	query string,
+	page int,
+) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	articles, total, err := searcher.SearchArticles(ctx, query, searchPageSize, uint64(page*searchPageSize))
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(articles) == 0 {
+		if page > 0 {
+			return fmt.Sprintf("No more results for %q.", query), nil, nil
+		}
+
+		return fmt.Sprintf("Nothing found for %q.", query), nil, nil
+	}
+
+	pages := (total + searchPageSize - 1) / searchPageSize
+
+	lines := []string{
+		fmt.Sprintf("Results for %q (page %d of %d, %d found):", query, page+1, pages, total),
+	}
+
+	for i, article := range articles {
+		lines = append(lines, "", formatSearchResult(page*searchPageSize+i+1, article))
+	}
+
+	var buttons []tgbotapi.InlineKeyboardButton
+
+	if page > 0 {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+			"« Prev", botkit.CallbackData(CallbackSearch, token, strconv.Itoa(page-1)),
+		))
+	}
+	if page+1 < pages {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+			"Next »", botkit.CallbackData(CallbackSearch, token, strconv.Itoa(page+1)),
+		))
+	}
+
+	if len(buttons) == 0 {
+		return truncateMessage(strings.Join(lines, "\n")), nil, nil
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(buttons)
+
+	return truncateMessage(strings.Join(lines, "\n")), &keyboard, nil
+}
+
+func formatSearchResult(n int, article model.Article) string {
+	status := "not posted"
+	if article.PostedAt.Valid {
+		status = "posted " + article.PostedAt.Time.Format("2006-01-02 15:04")
+	}
+
+	return fmt.Sprintf(
+		"%d. %s\n%s · %s\n%s",
+		n,
+		article.Title,
+		article.PublishedAt.Format("2006-01-02"),
+		status,
+		article.Link,
+	)
+}
@@ -0,0 +1,91 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/digest"
+	"news-feed-bot/internal/model"
+	"strings"
+	"time"
+)
+
+type ChannelDigestUpdater interface {
+	// UpdateDigest reports whether the channel exists.
+	UpdateDigest(ctx context.Context, id int64, digest model.ChannelDigest) (bool, error)
+}
+
+// ViewCmdSetDigest switches a channel between regular posts and a daily or
+// weekly digest, e.g. {"channel": 2, "mode": "weekly", "weekday": "monday",
+// "time": "09:00", "size": 15}. {"channel": 2, "mode": "off"} goes back to
+// regular posts.
+func ViewCmdSetDigest(updater ChannelDigestUpdater) botkit.ViewFunc {
+	const op = "bot.ViewCmdSetDigest"
+
+	type setDigestArgs struct {
+		ChannelID int64  `json:"channel"`
+		Mode      string `json:"mode"`
+		Time      string `json:"time"`
+		Weekday   string `json:"weekday"`
+		Size      int    `json:"size"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[setDigestArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		weekday := time.Monday
+		if args.Weekday != "" {
+			var ok bool
+
+			weekday, ok = digest.ParseWeekday(args.Weekday)
+			if !ok {
+				if err := replyText(bot, update, fmt.Sprintf("Unknown weekday %q.", args.Weekday)); err != nil {
+					return fmt.Errorf("%s: %w", op, err)
+				}
+
+				return nil
+			}
+		}
+
+		settings, err := digest.Normalize(model.ChannelDigest{
+			Mode:    args.Mode,
+			Time:    args.Time,
+			Weekday: weekday,
+			Size:    args.Size,
+		})
+		if err != nil {
+			if !errors.Is(err, digest.ErrInvalidDigest) {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			msgText := fmt.Sprintf("Invalid digest: %s\n\nModes: off, %s", err, strings.Join(digest.Modes, ", "))
+
+			if err := replyText(bot, update, msgText); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		found, err := updater.UpdateDigest(ctx, args.ChannelID, settings)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		msgText := fmt.Sprintf("Channel #%d: %s.", args.ChannelID, digest.Describe(settings))
+		if !found {
+			msgText = fmt.Sprintf("There is no channel #%d. See /channels.", args.ChannelID)
+		}
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/storage"
+	"strings"
+)
+
+type SourceGroupUpdater interface {
+	UpdateGroup(ctx context.Context, id int64, group string) error
+}
+
+// ViewCmdSetGroup puts a source in a group that channel routes can refer
+// to. An empty group removes the source from its group.
+func ViewCmdSetGroup(updater SourceGroupUpdater) botkit.ViewFunc {
+	const op = "bot.ViewCmdSetGroup"
+
+	type setGroupArgs struct {
+		ID    int64  `json:"id"`
+		Group string `json:"group"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[setGroupArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		group := strings.TrimSpace(args.Group)
+
+		var msgText string
+
+		err = updater.UpdateGroup(ctx, args.ID, group)
+		switch {
+		case errors.Is(err, storage.ErrSourceNotFound):
+			msgText = fmt.Sprintf("There is no source #%d. See /listsources.", args.ID)
+		case err != nil:
+			return fmt.Errorf("%s: %w", op, err)
+		case group == "":
+			msgText = fmt.Sprintf("Source %d is not in a group.", args.ID)
+		default:
+			msgText = fmt.Sprintf("Source %d is in group %q.", args.ID, group)
+		}
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/storage"
+	"strings"
+)
+
+type SourceLanguagesUpdater interface {
+	UpdateLanguages(ctx context.Context, id int64, languages []string) error
+}
+
+// ViewCmdSetLanguages sets the languages allowed for the items of a source.
+// An empty list makes the source follow the global allowed languages.
+func ViewCmdSetLanguages(updater SourceLanguagesUpdater) botkit.ViewFunc {
+	const op = "bot.ViewCmdSetLanguages"
+
+	type setLanguagesArgs struct {
+		ID        int64    `json:"id"`
+		Languages []string `json:"languages"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[setLanguagesArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		languages := normalizeLanguages(args.Languages)
+
+		var msgText string
+
+		err = updater.UpdateLanguages(ctx, args.ID, languages)
+		switch {
+		case errors.Is(err, storage.ErrSourceNotFound):
+			msgText = fmt.Sprintf("There is no source #%d. See /listsources.", args.ID)
+		case err != nil:
+			return fmt.Errorf("%s: %w", op, err)
+		default:
+			msgText = fmt.Sprintf("Languages of source %d: %s", args.ID, formatLanguages(languages))
+		}
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+// normalizeLanguages lowercases language codes and drops blank ones.
+func normalizeLanguages(languages []string) []string {
+	var normalized []string
+
+	for _, l := range languages {
+		if l = strings.ToLower(strings.TrimSpace(l)); l != "" {
+			normalized = append(normalized, l)
+		}
+	}
+
+	return normalized
+}
+
+func formatLanguages(languages []string) string {
+	if len(languages) == 0 {
+		return "global default"
+	}
+
+	return strings.Join(languages, ", ")
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/storage"
+)
+
+type SourcePriorityUpdater interface {
+	UpdatePriority(ctx context.Context, id int64, priority float64) error
+}
+
+// ViewCmdSetPriority sets the weight of a source in the selection of the
+// next post. 1 is neutral, higher values favour the source.
+func ViewCmdSetPriority(updater SourcePriorityUpdater) botkit.ViewFunc {
+	const op = "bot.ViewCmdSetPriority"
+
+	type setPriorityArgs struct {
+		ID int64 `json:"id"`
+		// Priority is a pointer so a missing value is not taken for 0.
+		Priority *float64 `json:"priority"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[setPriorityArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		var msgText string
+
+		switch {
+		case args.Priority == nil:
+			msgText = `Usage: /setpriority {"id": <source id>, "priority": <weight>}`
+		case *args.Priority < 0:
+			msgText = "Priority must not be negative."
+		default:
+			err := updater.UpdatePriority(ctx, args.ID, *args.Priority)
+			switch {
+			case errors.Is(err, storage.ErrSourceNotFound):
+				msgText = fmt.Sprintf("There is no source #%d. See /listsources.", args.ID)
+			case err != nil:
+				return fmt.Errorf("%s: %w", op, err)
+			default:
+				msgText = fmt.Sprintf("Priority of source %d: %g", args.ID, *args.Priority)
+			}
+		}
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/botkit/markup"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/storage"
+	"strings"
+	"time"
+)
+
+type SourceHealthStorage interface {
+	Sources(ctx context.Context) ([]model.Source, error)
+	ResumeSource(ctx context.Context, id int64) error
+}
+
+// ViewCmdSourceHealth lists paused and failing sources. Called with
+// {"enable": <id>} it re-enables a paused source instead.
+func ViewCmdSourceHealth(sources SourceHealthStorage) botkit.ViewFunc {
+	const op = "bot.ViewCmdSourceHealth"
+
+	type sourceHealthArgs struct {
+		Enable int64 `json:"enable"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		if rawArgs := update.Message.CommandArguments(); rawArgs != "" {
+			args, err := botkit.ParseJSON[sourceHealthArgs](rawArgs)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if err := sources.ResumeSource(ctx, args.Enable); err != nil {
+				if !errors.Is(err, storage.ErrSourceNotFound) {
+					return fmt.Errorf("%s: %w", op, err)
+				}
+
+				if err := replyText(bot, update, fmt.Sprintf("There is no source #%d. See /listsources.", args.Enable)); err != nil {
+					return fmt.Errorf("%s: %w", op, err)
+				}
+
+				return nil
+			}
+
+			reply := tgbotapi.NewMessage(
+				update.Message.Chat.ID,
+				fmt.Sprintf("Source `%d` was re\\-enabled and will be fetched shortly\\.", args.Enable),
+			)
+			reply.ParseMode = "MarkdownV2"
+
+			if _, err := bot.Send(reply); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		list, err := sources.Sources(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		var healthInfos []string
+
+		for _, source := range list {
+			if !source.Health.Paused && source.Health.ConsecutiveFailures == 0 {
+				continue
+			}
+
+			healthInfos = append(healthInfos, formatSourceHealth(source))
+		}
+
+		msgText := "All sources are healthy\\."
+		if len(healthInfos) > 0 {
+			msgText = fmt.Sprintf(
+				"Unhealthy sources \\(total %d\\):\n\n%s\n\n"+
+					"Re\\-enable a paused source with `/sourcehealth {\"enable\": <id>}`",
+				len(healthInfos),
+				strings.Join(healthInfos, "\n\n"),
+			)
+		}
+
+		reply := tgbotapi.NewMessage(update.Message.Chat.ID, msgText)
+		reply.ParseMode = "MarkdownV2"
+
+		if _, err := bot.Send(reply); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+func formatSourceHealth(source model.Source) string {
+	status := fmt.Sprintf("failing, %d in a row", source.Health.ConsecutiveFailures)
+	if source.Health.Paused {
+		status = "paused: " + source.Health.PausedReason
+	}
+
+	lines := []string{
+		fmt.Sprintf("⚪ *%s*", markup.EscapeForMarkdown(source.Name)),
+		fmt.Sprintf("ID: `%d`", source.ID),
+		"Status: " + markup.EscapeForMarkdown(status),
+		"Last success: " + markup.EscapeForMarkdown(formatHealthTime(source.Health.LastSuccessAt)),
+		"Last new item: " + markup.EscapeForMarkdown(formatHealthTime(source.Health.LastNewItemAt)),
+	}
+
+	if source.Health.LastError != "" {
+		lines = append(lines, fmt.Sprintf(
+			"Last error \\(%s\\): %s",
+			markup.EscapeForMarkdown(formatHealthTime(source.Health.LastErrorAt)),
+			markup.EscapeForMarkdown(source.Health.LastError),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatHealthTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+
+	return t.Format(time.DateTime)
+}
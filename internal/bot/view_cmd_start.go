@@ -13,6 +13,7 @@ func ViewCmdStart() botkit.ViewFunc {
 	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
 		msgTxt := fmt.Sprintf("Greetings!" +
 			"\n\nThis bot is designed for scheduled publication\nof articles from specified sources" +
+			"\n\nSend /subscribe in a private chat to get articles of chosen sources here" +
 			"\n\nType /commands	 to see available commands")
 
 		if _, err := bot.Send(tgbotapi.NewMessage(update.FromChat().ID, msgTxt)); err != nil {
@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/model"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Subscriber interface {
+	Subscribe(ctx context.Context, chatID int64, username string, sub model.Subscription) (bool, error)
+	Subscriptions(ctx context.Context, chatID int64) ([]model.Subscription, error)
+}
+
+// ViewCmdSubscribe subscribes the user to a source, given by its id or name,
+// or to a source group. Without arguments it lists what can be subscribed to.
+func ViewCmdSubscribe(storage Subscriber, sources SourceLister) botkit.ViewFunc {
+	const op = "bot.ViewCmdSubscribe"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		list, err := sources.Sources(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		rawArgs := strings.TrimSpace(update.Message.CommandArguments())
+		if rawArgs == "" {
+			current, err := storage.Subscriptions(ctx, update.FromChat().ID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if err := replyLines(bot, update, subscriptionOptions(list, current)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		sub, ok := subscriptionTarget(rawArgs, list)
+		if !ok {
+			if err := replyText(bot, update, fmt.Sprintf("There is no source or group %q. Send /subscribe to see them.", rawArgs)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		created, err := storage.Subscribe(ctx, update.FromChat().ID, update.SentFrom().UserName, sub)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		msgText := "You are subscribed to " + describeSubscription(sub, list) + ". New articles will be sent here."
+		if !created {
+			msgText = "You are already subscribed to " + describeSubscription(sub, list) + "."
+		}
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+// subscriptionTarget resolves the argument of /subscribe and /unsubscribe:
+// a source id, a group or a source name, in that order.
+func subscriptionTarget(arg string, sources []model.Source) (model.Subscription, bool) {
+	if id, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		for _, src := range sources {
+			if src.ID == id {
+				return model.Subscription{SourceID: id}, true
+			}
+		}
+
+		return model.Subscription{}, false
+	}
+
+	for _, src := range sources {
+		if src.Group != "" && strings.EqualFold(src.Group, arg) {
+			return model.Subscription{Group: src.Group}, true
+		}
+	}
+
+	for _, src := range sources {
+		if strings.EqualFold(src.Name, arg) {
+			return model.Subscription{SourceID: src.ID}, true
+		}
+	}
+
+	return model.Subscription{}, false
+}
+
+func describeSubscription(sub model.Subscription, sources []model.Source) string {
+	if sub.Group != "" {
+		return fmt.Sprintf("group %q", sub.Group)
+	}
+
+	for _, src := range sources {
+		if src.ID == sub.SourceID {
+			return fmt.Sprintf("%s (%d)", src.Name, src.ID)
+		}
+	}
+
+	return fmt.Sprintf("source %d", sub.SourceID)
+}
+
+func subscriptionOptions(sources []model.Source, current []model.Subscription) []string {
+	lines := []string{"Send /subscribe with a source id, source name or group.", "", "Sources:"}
+
+	groups := make(map[string]bool)
+
+	for _, src := range sources {
+		if src.Health.Paused {
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%d %s", src.ID, src.Name))
+
+		if src.Group != "" {
+			groups[src.Group] = true
+		}
+	}
+
+	if len(groups) > 0 {
+		names := make([]string, 0, len(groups))
+		for group := range groups {
+			names = append(names, group)
+		}
+		sort.Strings(names)
+
+		lines = append(lines, "", "Groups: "+strings.Join(names, ", "))
+	}
+
+	if len(current) > 0 {
+		lines = append(lines, "", "Your subscriptions:")
+
+		for _, sub := range current {
+			lines = append(lines, describeSubscription(sub, sources))
+		}
+	}
+
+	return lines
+}
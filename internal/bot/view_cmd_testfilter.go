@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/filter"
+	"news-feed-bot/internal/model"
+)
+
+const (
+	defaultTestFilterLimit = 50
+	maxTestFilterLimit     = 500
+)
+
+type LatestArticlesProvider interface {
+	LatestArticles(ctx context.Context, sourceID int64, limit uint64) ([]model.Article, error)
+}
+
+// ViewCmdTestFilter runs a candidate rule against the latest stored articles
+// and lists the ones it would have blocked. It takes the arguments of
+// /addfilter plus an optional limit.
+func ViewCmdTestFilter(articles LatestArticlesProvider) botkit.ViewFunc {
+	const op = "bot.ViewCmdTestFilter"
+
+	type testFilterArgs struct {
+		filterRuleArgs
+		Limit uint64 `json:"limit"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args, err := botkit.ParseJSON[testFilterArgs](update.Message.CommandArguments())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		rule := args.rule()
+
+		engine := filter.New()
+		if err := engine.Add(rule); err != nil {
+			if !errors.Is(err, filter.ErrInvalidRule) {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if err := replyText(bot, update, invalidRuleText(err)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		limit := args.Limit
+		if limit == 0 {
+			limit = defaultTestFilterLimit
+		}
+		limit = min(limit, maxTestFilterLimit)
+
+		latest, err := articles.LatestArticles(ctx, rule.SourceID, limit)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		var blocked []string
+
+		for _, article := range latest {
+			if !engine.Check(article.SourceID, filter.ArticleItem(article)).Keep {
+				blocked = append(blocked, fmt.Sprintf("• %s\n  %s", article.Title, article.Link))
+			}
+		}
+
+		lines := []string{
+			fmt.Sprintf("%s would block %d of the last %d articles.", filter.Describe(rule), len(blocked), len(latest)),
+		}
+
+		// Categories and authors are not stored, so rules on them only show
+		// their effect on newly fetched items.
+		if rule.Field == filter.FieldCategory || rule.Field == filter.FieldAuthor {
+			lines = append(lines, "Categories and authors are not stored, so the rule matched none of them.")
+		}
+
+		if len(blocked) > 0 {
+			lines = append(append(lines, ""), blocked...)
+		}
+
+		if err := replyLines(bot, update, lines); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/model"
+	"strings"
+)
+
+type Unsubscriber interface {
+	// Unsubscribe reports whether the subscription existed.
+	Unsubscribe(ctx context.Context, chatID int64, sub model.Subscription) (bool, error)
+	Subscriptions(ctx context.Context, chatID int64) ([]model.Subscription, error)
+}
+
+// ViewCmdUnsubscribe removes a subscription of the user. Without arguments
+// it lists the subscriptions.
+func ViewCmdUnsubscribe(storage Unsubscriber, sources SourceLister) botkit.ViewFunc {
+	const op = "bot.ViewCmdUnsubscribe"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		list, err := sources.Sources(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		rawArgs := strings.TrimSpace(update.Message.CommandArguments())
+		if rawArgs == "" {
+			current, err := storage.Subscriptions(ctx, update.FromChat().ID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			lines := []string{"You have no subscriptions."}
+			if len(current) > 0 {
+				lines = []string{"Send /unsubscribe with one of your subscriptions:", ""}
+
+				for _, sub := range current {
+					lines = append(lines, describeSubscription(sub, list))
+				}
+			}
+
+			if err := replyLines(bot, update, lines); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		sub, ok := subscriptionTarget(rawArgs, list)
+		if !ok {
+			if err := replyText(bot, update, fmt.Sprintf("There is no source or group %q.", rawArgs)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		deleted, err := storage.Unsubscribe(ctx, update.FromChat().ID, sub)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		msgText := "You are unsubscribed from " + describeSubscription(sub, list) + "."
+		if !deleted {
+			msgText = "You are not subscribed to " + describeSubscription(sub, list) + "."
+		}
+
+		if err := replyText(bot, update, msgText); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/source"
+	"time"
+)
+
+// IsChannelForward matches messages forwarded to the bot from a channel in
+// a private chat.
+func IsChannelForward(msg *tgbotapi.Message) bool {
+	return msg.Chat != nil && msg.Chat.IsPrivate() &&
+		msg.ForwardFromChat != nil && msg.ForwardFromChat.IsChannel()
+}
+
+// ViewMsgAddChannel previews the channel a forwarded message came from as a
+// telegram source. It is stored once the admin confirms, as with /addsource.
+func ViewMsgAddChannel(storage SourceStorage, kinds SourceKinds, pending *PendingSources) botkit.ViewFunc {
+	const op = "bot.ViewMsgAddChannel"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		channel := update.Message.ForwardFromChat
+
+		if channel.UserName == "" {
+			if err := replyText(bot, update, "Only public channels with a username can be added as a source"); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		src := model.Source{
+			Name:      channel.Title,
+			FeedURL:   source.TelegramChannelURL(channel.UserName),
+			Kind:      source.KindTelegram,
+			CreatedAt: time.Now().UTC(),
+		}
+
+		existing, err := storage.Sources(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if dup, ok := findDuplicate(existing, src.FeedURL); ok {
+			if err := replyText(bot, update, duplicateText(dup)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		text, markup := previewSource(ctx, kinds, pending, src)
+
+		reply := tgbotapi.NewMessage(update.Message.Chat.ID, text)
+		if len(markup.InlineKeyboard) > 0 {
+			reply.ReplyMarkup = markup
+		}
+
+		if _, err := bot.Send(reply); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,195 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"io"
+	"net/http"
+	"net/url"
+	"news-feed-bot/internal/botkit"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/opml"
+	"news-feed-bot/internal/source"
+	"news-feed-bot/internal/urlutil"
+	"strings"
+	"time"
+)
+
+// maxOPMLSize caps the size of an uploaded OPML document.
+const maxOPMLSize = 5 << 20
+
+type SourceImporter interface {
+	Sources(ctx context.Context) ([]model.Source, error)
+	Add(ctx context.Context, source model.Source) (int64, error)
+}
+
+// IsOPMLDocument matches uploaded files with an .opml extension.
+func IsOPMLDocument(msg *tgbotapi.Message) bool {
+	return msg.Document != nil &&
+		strings.HasSuffix(strings.ToLower(msg.Document.FileName), ".opml")
+}
+
+// ViewMsgImportOPML adds the feeds of an uploaded OPML document as sources.
+// Feeds already registered under the same normalized URL are skipped.
+func ViewMsgImportOPML(storage SourceImporter, kinds SourceKinds) botkit.ViewFunc {
+	const op = "bot.ViewMsgImportOPML"
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		if update.Message.Document.FileSize > maxOPMLSize {
+			if err := replyText(bot, update, "The OPML file is too large."); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		data, err := downloadOPML(ctx, bot, update.Message.Document.FileID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		feeds, err := opml.Parse(bytes.NewReader(data))
+		if err != nil {
+			if err := replyText(bot, update, "The file is not a valid OPML document."); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+
+		existing, err := storage.Sources(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		known := make(map[string]bool, len(existing))
+		for _, src := range existing {
+			known[urlutil.Normalize(src.FeedURL)] = true
+		}
+
+		var (
+			results []string
+			added   int
+		)
+
+		for _, feed := range feeds {
+			result, ok, err := importFeed(ctx, storage, kinds, known, feed)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if ok {
+				added++
+			}
+
+			results = append(results, result)
+		}
+
+		results = append(results, fmt.Sprintf("\nAdded %d of %d feeds.", added, len(feeds)))
+
+		if err := replyLines(bot, update, results); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+}
+
+// importFeed adds a single OPML entry and describes the outcome.
+// Only storage failures are returned as errors.
+func importFeed(
+	ctx context.Context,
+	storage SourceImporter,
+	kinds SourceKinds,
+	known map[string]bool,
+	feed opml.Feed,
+) (string, bool, error) {
+	normalized := urlutil.Normalize(feed.URL)
+	if known[normalized] {
+		return "⏭ " + feed.Title + ": already added", false, nil
+	}
+
+	name := feed.Title
+	if name == "" {
+		name = feed.URL
+	}
+
+	if feed.Err != nil {
+		return "❌ " + name + ": " + feed.Err.Error(), false, nil
+	}
+
+	// OPML readers write type="rss" for every kind of feed, which the RSS
+	// parser handles; kinds of this bot round-trip as they are.
+	kind := feed.Type
+	if !kinds.Has(kind) {
+		kind = source.KindRSS
+	}
+
+	src := model.Source{
+		Name:      name,
+		FeedURL:   feed.URL,
+		Kind:      kind,
+		Selectors: feed.Selectors,
+		HTTP:      feed.HTTP,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if _, err := kinds.New(src); err != nil {
+		return "❌ " + name + ": " + err.Error(), false, nil
+	}
+
+	id, err := storage.Add(ctx, src)
+	if err != nil {
+		return "", false, err
+	}
+
+	known[normalized] = true
+
+	return fmt.Sprintf("✅ %s: added with id %d", name, id), true, nil
+}
+
+// downloadOPML fetches an uploaded file. The file URL contains the bot
+// token, so it is stripped from the errors returned.
+func downloadOPML(ctx context.Context, bot *tgbotapi.BotAPI, fileID string) ([]byte, error) {
+	const op = "bot.downloadOPML"
+
+	fileURL, err := bot.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, withoutURL(err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, withoutURL(err))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, withoutURL(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status %d", op, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOPMLSize))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, withoutURL(err))
+	}
+
+	return data, nil
+}
+
+// withoutURL unwraps a *url.Error so the request URL is not reported.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+
+	return err
+}
@@ -5,17 +5,40 @@ import (
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"log/slog"
+	"strings"
+	"sync"
 	"time"
 )
 
+// updateTimeout bounds the handling of a single update. It leaves room for
+// views that download feeds before replying.
+const updateTimeout = 30 * time.Second
+
+// maxConcurrentUpdates is how many updates are handled at once, so a view
+// waiting on a slow site does not hold up the others.
+const maxConcurrentUpdates = 8
+
+// CallbackSeparator splits the view prefix from the payload in callback data.
+const CallbackSeparator = ":"
+
 type Bot struct {
-	api      *tgbotapi.BotAPI
-	CmdViews map[string]ViewFunc
-	log      *slog.Logger
+	api           *tgbotapi.BotAPI
+	CmdViews      map[string]ViewFunc
+	msgViews      []messageView
+	callbackViews map[string]ViewFunc
+	log           *slog.Logger
 }
 
 type ViewFunc func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error
 
+// MatchFunc reports whether a non-command message should be handled by a view.
+type MatchFunc func(msg *tgbotapi.Message) bool
+
+type messageView struct {
+	match MatchFunc
+	view  ViewFunc
+}
+
 func New(log *slog.Logger, api *tgbotapi.BotAPI) *Bot {
 	return &Bot{
 		api: api,
@@ -33,13 +56,39 @@ func (b *Bot) Run(ctx context.Context) error {
 
 	updates := b.api.GetUpdatesChan(u)
 
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentUpdates)
+	)
+
 	for {
 		select {
 		case update := <-updates:
-			updateCtx, updateCancel := context.WithTimeout(ctx, 5*time.Second)
-			b.handleUpdate(updateCtx, update)
-			updateCancel()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				b.api.StopReceivingUpdates()
+				wg.Wait()
+				return fmt.Errorf("%s: %w", op, ctx.Err())
+			}
+
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				// Updates being handled when ctx is cancelled are finished
+				// within their own timeout.
+				updateCtx, updateCancel := context.WithTimeout(context.WithoutCancel(ctx), updateTimeout)
+				defer updateCancel()
+
+				b.handleUpdate(updateCtx, update)
+			}()
 		case <-ctx.Done():
+			b.api.StopReceivingUpdates()
+			wg.Wait()
 			return fmt.Errorf("%s: %w", op, ctx.Err())
 		}
 	}
@@ -53,35 +102,91 @@ func (b *Bot) RegisterCmdView(cmd string, view ViewFunc) {
 	b.CmdViews[cmd] = view
 }
 
+// RegisterMessageView routes non-command messages accepted by match to view.
+// Views are tried in registration order and only the first match runs.
+func (b *Bot) RegisterMessageView(match MatchFunc, view ViewFunc) {
+	b.msgViews = append(b.msgViews, messageView{match: match, view: view})
+}
+
+// RegisterCallbackView routes inline keyboard presses whose callback data
+// starts with prefix followed by CallbackSeparator to view.
+func (b *Bot) RegisterCallbackView(prefix string, view ViewFunc) {
+	if b.callbackViews == nil {
+		b.callbackViews = make(map[string]ViewFunc)
+	}
+
+	b.callbackViews[prefix] = view
+}
+
+// CallbackData builds callback data routed to the view registered for prefix.
+func CallbackData(prefix string, payload ...string) string {
+	return strings.Join(append([]string{prefix}, payload...), CallbackSeparator)
+}
+
+// CallbackPayload returns the parts of the callback data after the prefix.
+func CallbackPayload(update tgbotapi.Update) []string {
+	parts := strings.Split(update.CallbackData(), CallbackSeparator)
+
+	return parts[1:]
+}
+
 func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 	defer func() {
 		if p := recover(); p != nil {
-			b.log.Info("%w", p)
+			b.log.Error("panic while handling update", "panic", p)
 		}
 	}()
 
-	if update.Message == nil || !update.Message.IsCommand() {
+	view := b.resolveView(update)
+	if view == nil {
 		return
 	}
 
-	var view ViewFunc
-
-	cmd := update.Message.Command()
-
-	cmdView, ok := b.CmdViews[cmd]
-	if !ok {
-		return
+	if update.CallbackQuery != nil {
+		// Stop the spinner on the pressed button whatever the view does.
+		defer func() {
+			if _, err := b.api.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, "")); err != nil {
+				b.log.Error("failed to answer callback query", "err", err)
+			}
+		}()
 	}
 
-	view = cmdView
-
 	if err := view(ctx, b.api, update); err != nil {
-		b.log.Info("%w", err)
+		b.log.Error("failed to handle update", "err", err)
 
 		if _, err := b.api.Send(
-			tgbotapi.NewMessage(update.Message.Chat.ID, "internal error"),
+			tgbotapi.NewMessage(update.FromChat().ID, "internal error"),
 		); err != nil {
-			b.log.Info("%w", err)
+			b.log.Error("failed to send error reply", "err", err)
 		}
 	}
 }
+
+func (b *Bot) resolveView(update tgbotapi.Update) ViewFunc {
+	switch {
+	case update.CallbackQuery != nil:
+		if update.CallbackQuery.Message == nil {
+			return nil
+		}
+
+		prefix, _, _ := strings.Cut(update.CallbackData(), CallbackSeparator)
+
+		return b.callbackViews[prefix]
+	case update.Message == nil:
+		return nil
+	case update.Message.IsCommand():
+		return b.CmdViews[update.Message.Command()]
+	}
+
+	return b.matchMessageView(update.Message)
+}
+
+func (b *Bot) matchMessageView(msg *tgbotapi.Message) ViewFunc {
+	for _, mv := range b.msgViews {
+		if mv.match(msg) {
+			return mv.view
+		}
+	}
+
+	return nil
+}
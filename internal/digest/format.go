@@ -0,0 +1,165 @@
+package digest
+
+import (
+	"errors"
+	"fmt"
+	"news-feed-bot/internal/botkit/markup"
+	"news-feed-bot/internal/extract"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/scoring"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxMessageLen is the Telegram limit for the text of a single message.
+	maxMessageLen = 4096
+	maxSummaryLen = 160
+)
+
+// linkEscaper escapes a URL inside the parentheses of a MarkdownV2 link.
+var linkEscaper = strings.NewReplacer(`\`, `\\`, `)`, `\)`)
+
+type section struct {
+	heading string
+	items   []string
+}
+
+// Format renders a digest as MarkdownV2 messages. Articles are grouped by
+// source, sources ordered by their best article, and numbered through.
+// Messages are split between items, repeating the source heading.
+func Format(d model.ChannelDigest, at time.Time, scored []scoring.Scored) []string {
+	var (
+		order    []int64
+		bySource = make(map[int64][]scoring.Scored)
+	)
+
+	for _, candidate := range scored {
+		sourceID := candidate.Article.SourceID
+
+		if _, ok := bySource[sourceID]; !ok {
+			order = append(order, sourceID)
+		}
+
+		bySource[sourceID] = append(bySource[sourceID], candidate)
+	}
+
+	sections := make([]section, 0, len(order))
+	n := 0
+
+	for _, sourceID := range order {
+		articles := bySource[sourceID]
+
+		name := articles[0].Source.Name
+		if name == "" {
+			name = fmt.Sprintf("Source %d", sourceID)
+		}
+
+		sec := section{heading: "*" + markup.EscapeForMarkdown(name) + "*"}
+
+		for _, candidate := range articles {
+			n++
+			sec.items = append(sec.items, formatItem(n, candidate.Article))
+		}
+
+		sections = append(sections, sec)
+	}
+
+	return split(title(d, at), sections, maxMessageLen)
+}
+
+func title(d model.ChannelDigest, at time.Time) string {
+	name := "Daily digest"
+	if d.Mode == ModeWeekly {
+		name = "Weekly digest"
+	}
+
+	return "*" + markup.EscapeForMarkdown(name+", "+at.Format("2 January 2006")) + "*"
+}
+
+func formatItem(n int, article model.Article) string {
+	item := fmt.Sprintf(
+		"%s\\. [%s](%s)",
+		markup.EscapeForMarkdown(fmt.Sprint(n)),
+		markup.EscapeForMarkdown(article.Title),
+		linkEscaper.Replace(article.Link),
+	)
+
+	if summary := summaryLine(article); summary != "" {
+		item += " — " + markup.EscapeForMarkdown(summary)
+	}
+
+	return item
+}
+
+// summaryLine returns the first sentence of the feed summary, or of the
+// extracted text when the feed has none, cut to maxSummaryLen characters.
+func summaryLine(article model.Article) string {
+	text := article.Text
+
+	if article.Summary != "" {
+		content, err := extract.FromReader(strings.NewReader(article.Summary), nil)
+		if err == nil {
+			text = content.Text
+		} else if !errors.Is(err, extract.ErrNoContent) {
+			return ""
+		}
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+
+	for _, end := range []string{". ", "! ", "? "} {
+		if i := strings.Index(text, end); i > 0 {
+			text = text[:i+1]
+		}
+	}
+
+	if utf8.RuneCountInString(text) > maxSummaryLen {
+		runes := []rune(text)
+		text = strings.TrimSpace(string(runes[:maxSummaryLen-1])) + "…"
+	}
+
+	return text
+}
+
+// split joins the title and sections into messages of at most limit bytes.
+// A section continued in the next message gets its heading again.
+func split(title string, sections []section, limit int) []string {
+	var (
+		messages []string
+		sb       strings.Builder
+	)
+
+	sb.WriteString(title)
+
+	for _, sec := range sections {
+		heading := "\n\n" + sec.heading
+
+		if sb.Len()+len(heading) > limit {
+			messages = append(messages, sb.String())
+			sb.Reset()
+			heading = sec.heading
+		}
+
+		sb.WriteString(heading)
+
+		for _, item := range sec.items {
+			line := "\n" + item
+
+			if sb.Len()+len(line) > limit {
+				messages = append(messages, sb.String())
+				sb.Reset()
+				sb.WriteString(sec.heading)
+			}
+
+			sb.WriteString(line)
+		}
+	}
+
+	if sb.Len() > 0 {
+		messages = append(messages, sb.String())
+	}
+
+	return messages
+}
@@ -0,0 +1,117 @@
+package digest
+
+import (
+	"fmt"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/scoring"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplit(t *testing.T) {
+	item := func(n int) string {
+		return fmt.Sprintf("%d\\. [Article number %d](https://example.com/%d) — %s", n, n, n, strings.Repeat("x", 100))
+	}
+
+	sections := func(counts ...int) []section {
+		var (
+			result []section
+			n      int
+		)
+
+		for i, count := range counts {
+			sec := section{heading: fmt.Sprintf("*Source %d*", i+1)}
+			for j := 0; j < count; j++ {
+				n++
+				sec.items = append(sec.items, item(n))
+			}
+
+			result = append(result, sec)
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		sections []section
+		limit    int
+		messages int
+	}{
+		{"empty", nil, maxMessageLen, 1},
+		{"fits one message", sections(3, 2), maxMessageLen, 1},
+		{"long section", sections(100), maxMessageLen, 4},
+		{"many sections", sections(30, 30, 30, 30), maxMessageLen, 5},
+		{"heading does not fit", sections(1, 1), len("*Title*") + len("\n\n*Source 1*\n"+item(1)) + 5, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := split("*Title*", tt.sections, tt.limit)
+
+			if len(messages) != tt.messages {
+				t.Errorf("got %d messages, want %d", len(messages), tt.messages)
+			}
+
+			if !strings.HasPrefix(messages[0], "*Title*") {
+				t.Errorf("first message %q does not start with the title", messages[0])
+			}
+
+			for i, msg := range messages {
+				if len(msg) > tt.limit {
+					t.Errorf("message %d is %d bytes, over the limit of %d", i, len(msg), tt.limit)
+				}
+
+				if i > 0 && !strings.HasPrefix(msg, "*Source ") {
+					t.Errorf("message %d does not start with a source heading: %.40q", i, msg)
+				}
+			}
+
+			// Every item is sent once, in order.
+			joined := strings.Join(messages, "\n")
+			offset := 0
+
+			for _, sec := range tt.sections {
+				for _, it := range sec.items {
+					i := strings.Index(joined[offset:], it)
+					if i < 0 {
+						t.Fatalf("item %.30q is missing or out of order", it)
+					}
+
+					offset += i + len(it)
+				}
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	scored := []scoring.Scored{
+		{Article: model.Article{SourceID: 1, Title: "First (one)", Link: "https://example.com/a_(1)"}, Source: model.Source{ID: 1, Name: "Blog"}},
+		{Article: model.Article{SourceID: 2, Title: "Second", Link: "https://example.com/b"}},
+		{Article: model.Article{SourceID: 1, Title: "Third", Link: "https://example.com/c"}, Source: model.Source{ID: 1, Name: "Blog"}},
+	}
+
+	at := time.Date(2024, time.June, 3, 9, 0, 0, 0, time.UTC)
+
+	messages := Format(model.ChannelDigest{Mode: ModeDaily}, at, scored)
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+
+	want := strings.Join([]string{
+		"*Daily digest, 3 June 2024*",
+		"",
+		"*Blog*",
+		`1\. [First \(one\)](https://example.com/a_(1\))`,
+		`2\. [Third](https://example.com/c)`,
+		"",
+		"*Source 2*",
+		`3\. [Second](https://example.com/b)`,
+	}, "\n")
+
+	if messages[0] != want {
+		t.Errorf("Format =\n%s\nwant\n%s", messages[0], want)
+	}
+}
@@ -0,0 +1,170 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"log/slog"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/scoring"
+	"time"
+)
+
+type ArticleRanker interface {
+	RankWindow(ctx context.Context, channelID int64, since time.Time, candidates int, now time.Time) ([]scoring.Scored, error)
+}
+
+type ChannelStorage interface {
+	Channels(ctx context.Context) ([]model.Channel, error)
+	CompleteDigest(ctx context.Context, channelID int64, articleIDs []int64, at time.Time) error
+}
+
+// Publisher posts a digest of the best articles to the channels in digest
+// mode once their scheduled time has come.
+type Publisher struct {
+	ranker     ArticleRanker
+	channels   ChannelStorage
+	bot        *tgbotapi.BotAPI
+	tick       time.Duration
+	candidates int
+	loc        *time.Location
+	log        *slog.Logger
+}
+
+// New builds a publisher checking every tick for due digests, each picked
+// from up to candidates articles of its period. Digest times are in loc.
+func New(
+	ranker ArticleRanker,
+	channels ChannelStorage,
+	bot *tgbotapi.BotAPI,
+	tick time.Duration,
+	candidates int,
+	loc *time.Location,
+	log *slog.Logger,
+) *Publisher {
+	return &Publisher{
+		ranker:     ranker,
+		channels:   channels,
+		bot:        bot,
+		tick:       tick,
+		candidates: candidates,
+		loc:        loc,
+		log:        log,
+	}
+}
+
+func (p *Publisher) Start(ctx context.Context) error {
+	const op = "digest.Publisher.Start"
+
+	p.log.Info("digest publisher was started successfully")
+
+	ticker := time.NewTicker(p.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.Publish(ctx); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w", op, ctx.Err())
+		}
+	}
+}
+
+// Publish posts the digests that are due. A failing channel is logged and
+// does not hold back the others.
+func (p *Publisher) Publish(ctx context.Context) error {
+	const op = "digest.Publisher.Publish"
+
+	channels, err := p.channels.Channels(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+
+	for _, channel := range channels {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if !Due(channel.Digest, now, p.loc) {
+			continue
+		}
+
+		if err := p.publishTo(ctx, channel, now); err != nil {
+			p.log.Error("failed to publish digest",
+				"channel_id", channel.ID,
+				"chat_id", channel.ChatID,
+				"err", err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// publishTo posts the digest of the period ending at the latest scheduled
+// time. The period is marked done even when it had no articles, and as soon
+// as the first message of a digest split over several is sent: a failure
+// later on loses the remaining parts instead of posting the first ones again
+// on every tick.
+func (p *Publisher) publishTo(ctx context.Context, channel model.Channel, now time.Time) error {
+	occurrence := LastOccurrence(channel.Digest, now, p.loc)
+	since := occurrence.Add(-Period(channel.Digest.Mode))
+
+	ranked, err := p.ranker.RankWindow(ctx, channel.ID, since, p.candidates, now)
+	if err != nil {
+		return err
+	}
+
+	top := ranked[:min(len(ranked), max(channel.Digest.Size, 1))]
+	ids := make([]int64, 0, len(top))
+
+	for _, scored := range top {
+		ids = append(ids, scored.Article.ID)
+	}
+
+	p.log.Info("publishing digest",
+		"channel_id", channel.ID,
+		"candidates", len(ranked),
+		"articles", len(top),
+	)
+
+	var parts []string
+	if len(top) > 0 {
+		parts = Format(channel.Digest, occurrence, top)
+	}
+
+	if len(parts) > 0 {
+		if err := p.send(channel.ChatID, parts[0]); err != nil {
+			return err
+		}
+	}
+
+	// A digest sent when ctx is cancelled is still marked, so it is not
+	// posted twice after a restart.
+	if err := p.channels.CompleteDigest(context.WithoutCancel(ctx), channel.ID, ids, now); err != nil {
+		return err
+	}
+
+	for i := 1; i < len(parts); i++ {
+		if err := p.send(channel.ChatID, parts[i]); err != nil {
+			return fmt.Errorf("part %d of %d: %w", i+1, len(parts), err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Publisher) send(chatID int64, text string) error {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	msg.DisableWebPagePreview = true
+
+	_, err := p.bot.Send(msg)
+
+	return err
+}
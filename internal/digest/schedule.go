@@ -0,0 +1,129 @@
+package digest
+
+import (
+	"errors"
+	"fmt"
+	"news-feed-bot/internal/model"
+	"strings"
+	"time"
+)
+
+const (
+	ModeDaily  = "daily"
+	ModeWeekly = "weekly"
+)
+
+const (
+	DefaultSize = 10
+	MaxSize     = 50
+	DefaultTime = "09:00"
+)
+
+var ErrInvalidDigest = errors.New("invalid digest settings")
+
+var Modes = []string{ModeDaily, ModeWeekly}
+
+// Normalize validates d and applies the defaults. A mode of "" or "off"
+// turns digests off and clears the other settings.
+func Normalize(d model.ChannelDigest) (model.ChannelDigest, error) {
+	const op = "digest.Normalize"
+
+	d.Mode = strings.ToLower(strings.TrimSpace(d.Mode))
+
+	switch d.Mode {
+	case "", "off":
+		return model.ChannelDigest{}, nil
+	case ModeDaily, ModeWeekly:
+	default:
+		return model.ChannelDigest{}, fmt.Errorf("%s: %w: unknown mode %q", op, ErrInvalidDigest, d.Mode)
+	}
+
+	if d.Time == "" {
+		d.Time = DefaultTime
+	}
+
+	at, err := time.Parse("15:04", d.Time)
+	if err != nil {
+		return model.ChannelDigest{}, fmt.Errorf("%s: %w: time %q is not HH:MM", op, ErrInvalidDigest, d.Time)
+	}
+	d.Time = at.Format("15:04")
+
+	if d.Mode == ModeDaily {
+		d.Weekday = time.Sunday
+	}
+
+	switch {
+	case d.Size <= 0:
+		d.Size = DefaultSize
+	case d.Size > MaxSize:
+		return model.ChannelDigest{}, fmt.Errorf("%s: %w: size is over %d", op, ErrInvalidDigest, MaxSize)
+	}
+
+	return d, nil
+}
+
+// ParseWeekday accepts English weekday names and their three letter forms.
+func ParseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+
+	return time.Sunday, false
+}
+
+// Period is the time between two digests.
+func Period(mode string) time.Duration {
+	if mode == ModeWeekly {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+// LastOccurrence returns the latest time at or before now the digest of d
+// is scheduled for, with its time of day and weekday taken in loc.
+func LastOccurrence(d model.ChannelDigest, now time.Time, loc *time.Location) time.Time {
+	at, err := time.Parse("15:04", d.Time)
+	if err != nil {
+		at, _ = time.Parse("15:04", DefaultTime)
+	}
+
+	now = now.In(loc)
+	occurrence := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, loc)
+
+	if occurrence.After(now) {
+		occurrence = occurrence.AddDate(0, 0, -1)
+	}
+
+	if d.Mode == ModeWeekly {
+		for occurrence.Weekday() != d.Weekday {
+			occurrence = occurrence.AddDate(0, 0, -1)
+		}
+	}
+
+	return occurrence
+}
+
+// Due reports whether the digest of d scheduled at or before now has not
+// been posted yet.
+func Due(d model.ChannelDigest, now time.Time, loc *time.Location) bool {
+	return d.Mode != "" && LastOccurrence(d, now, loc).After(d.LastAt)
+}
+
+// Describe tells when the digest of d is posted. Times are in the time zone
+// of the posting schedule.
+func Describe(d model.ChannelDigest) string {
+	switch d.Mode {
+	case ModeDaily:
+		return fmt.Sprintf("daily digest of %d at %s", d.Size, d.Time)
+	case ModeWeekly:
+		return fmt.Sprintf("weekly digest of %d on %s at %s", d.Size, d.Weekday, d.Time)
+	}
+
+	return "posts every notification interval"
+}
@@ -0,0 +1,86 @@
+package digest
+
+import (
+	"news-feed-bot/internal/model"
+	"testing"
+	"time"
+	// Time zones load without a zoneinfo database on the host.
+	_ "time/tzdata"
+)
+
+func TestLastOccurrence(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	daily := model.ChannelDigest{Mode: ModeDaily, Time: "09:00"}
+	weekly := model.ChannelDigest{Mode: ModeWeekly, Time: "09:00", Weekday: time.Sunday}
+
+	// Clocks in Berlin go forward on 31 March 2024 and back on 27 October.
+	tests := []struct {
+		name string
+		d    model.ChannelDigest
+		now  time.Time
+		want time.Time
+	}{
+		{"later today", daily, utc(time.June, 3, 12, 0), utc(time.June, 3, 7, 0)},
+		{"at the time", daily, utc(time.June, 3, 7, 0), utc(time.June, 3, 7, 0)},
+		{"yesterday", daily, utc(time.June, 3, 6, 59), utc(time.June, 2, 7, 0)},
+		{"after clocks go forward", daily, utc(time.March, 31, 8, 0), utc(time.March, 31, 7, 0)},
+		{"before clocks go forward", daily, utc(time.March, 31, 6, 30), utc(time.March, 30, 8, 0)},
+		{"after clocks go back", daily, utc(time.October, 27, 9, 0), utc(time.October, 27, 8, 0)},
+		{"before clocks go back", daily, utc(time.October, 27, 7, 30), utc(time.October, 26, 7, 0)},
+		{"weekly on the day", weekly, utc(time.June, 2, 8, 0), utc(time.June, 2, 7, 0)},
+		{"weekly later in the week", weekly, utc(time.June, 5, 8, 0), utc(time.June, 2, 7, 0)},
+		{"weekly before the time", weekly, utc(time.June, 2, 6, 0), utc(time.May, 26, 7, 0)},
+		{"weekly across clocks going forward", weekly, utc(time.April, 2, 12, 0), utc(time.March, 31, 7, 0)},
+		{"weekly across clocks going back", weekly, utc(time.October, 29, 12, 0), utc(time.October, 27, 8, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LastOccurrence(tt.d, tt.now, berlin)
+
+			if !got.Equal(tt.want) {
+				t.Errorf("LastOccurrence(%s) = %s, want %s", tt.now, got.UTC(), tt.want)
+			}
+
+			if got.Location() != berlin {
+				t.Errorf("LastOccurrence(%s) is in %s, want %s", tt.now, got.Location(), berlin)
+			}
+		})
+	}
+}
+
+func TestLastOccurrenceSkippedTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 02:30 does not exist in Berlin on 31 March 2024. The digest is still
+	// posted that day, once.
+	d := model.ChannelDigest{Mode: ModeDaily, Time: "02:30"}
+	now := time.Date(2024, time.March, 31, 12, 0, 0, 0, berlin)
+
+	got := LastOccurrence(d, now, berlin)
+
+	if got.After(now) || got.YearDay() != now.YearDay() {
+		t.Fatalf("LastOccurrence = %s, want a time on %s before it", got, now)
+	}
+
+	d.LastAt = got
+	if Due(d, now, berlin) {
+		t.Errorf("Due after posting at %s", got)
+	}
+
+	d.LastAt = LastOccurrence(d, now.AddDate(0, 0, -1), berlin)
+	if !Due(d, now, berlin) {
+		t.Errorf("not Due after posting the day before at %s", d.LastAt)
+	}
+}
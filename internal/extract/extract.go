@@ -0,0 +1,151 @@
+package extract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-shiori/go-readability"
+	"golang.org/x/net/html"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"news-feed-bot/internal/httpclient"
+	"news-feed-bot/internal/model"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// maxPageSize bounds the article pages read for extraction.
+	maxPageSize = 5 << 20
+	// wordsPerMinute is the reading speed behind ReadingTime.
+	wordsPerMinute = 200
+)
+
+var ErrNoContent = errors.New("no readable content")
+
+var extraNewlines = regexp.MustCompile(`\n{3,}`)
+
+// Content is the readable part of an article page.
+type Content struct {
+	Text        string
+	Byline      string
+	LeadImage   string
+	WordCount   int
+	ReadingTime time.Duration
+}
+
+// HTTPClients hands out clients configured with per-source HTTP settings.
+type HTTPClients interface {
+	Client(settings *model.HTTPSettings, feedURL string) (*http.Client, error)
+}
+
+// Extractor downloads article pages and extracts their readable content.
+type Extractor struct {
+	clients HTTPClients
+}
+
+func New(clients HTTPClients) *Extractor {
+	return &Extractor{clients: clients}
+}
+
+// Extract downloads link with the HTTP settings of src and extracts its
+// content. Headers and credentials of src are only sent when link is on the
+// host of its feed.
+func (e *Extractor) Extract(ctx context.Context, src model.Source, link string) (Content, error) {
+	const op = "extract.Extract"
+
+	client, err := e.clients.Client(src.HTTP, src.FeedURL)
+	if err != nil {
+		return Content{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return Content{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Content{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Content{}, fmt.Errorf("%s: %w", op, httpclient.NewStatusError(resp))
+	}
+
+	content, err := FromReader(io.LimitReader(resp.Body, maxPageSize), resp.Request.URL)
+	if err != nil {
+		return Content{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return content, nil
+}
+
+// FromReader extracts the content of an HTML page or fragment. pageURL, if
+// not nil, resolves relative links such as the lead image.
+func FromReader(r io.Reader, pageURL *url.URL) (Content, error) {
+	article, err := readability.FromReader(r, pageURL)
+	if err != nil {
+		return Content{}, err
+	}
+
+	text := strings.TrimSpace(extraNewlines.ReplaceAllString(article.TextContent, "\n\n"))
+	if text == "" {
+		return Content{}, ErrNoContent
+	}
+
+	words := len(strings.Fields(text))
+
+	return Content{
+		Text:        text,
+		Byline:      strings.TrimSpace(article.Byline),
+		LeadImage:   article.Image,
+		WordCount:   words,
+		ReadingTime: readingTime(words),
+	}, nil
+}
+
+// PlainText returns the text of an HTML fragment such as a feed summary,
+// without tags and with entities decoded. Unlike FromReader it keeps all
+// the text, however short.
+func PlainText(fragment string) string {
+	var (
+		tokenizer = html.NewTokenizer(strings.NewReader(fragment))
+		parts     []string
+		skip      int
+	)
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.Join(parts, " ")
+		case html.StartTagToken:
+			if name, _ := tokenizer.TagName(); isHiddenTag(string(name)) {
+				skip++
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); isHiddenTag(string(name)) && skip > 0 {
+				skip--
+			}
+		case html.TextToken:
+			if skip == 0 {
+				parts = append(parts, strings.Fields(string(tokenizer.Text()))...)
+			}
+		}
+	}
+}
+
+func isHiddenTag(name string) bool {
+	return name == "script" || name == "style"
+}
+
+// readingTime rounds up to whole minutes, as reading times usually are.
+func readingTime(words int) time.Duration {
+	minutes := math.Ceil(float64(words) / wordsPerMinute)
+
+	return time.Duration(minutes) * time.Minute
+}
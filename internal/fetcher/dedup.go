@@ -0,0 +1,174 @@
+package fetcher
+
+import (
+	"context"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/simhash"
+	"news-feed-bot/internal/urlutil"
+	"sync"
+	"time"
+)
+
+// minTitleWords is the shortest title compared for near-duplicates. Shorter
+// titles share too few words for their hashes to mean anything.
+const minTitleWords = 4
+
+// titleGramSize is the length of the character grams hashed for a title.
+const titleGramSize = 4
+
+// resolvedLinkTTL is how long a resolved redirect is remembered after its
+// link was last seen in a feed.
+const resolvedLinkTTL = 24 * time.Hour
+
+// DedupPolicy controls detection of the same story published under
+// different links or by several sources.
+type DedupPolicy struct {
+	// Window is how far back stored articles are compared with new items.
+	Window time.Duration
+	// MaxDistance is the largest number of differing bits between two title
+	// hashes that still counts as the same story.
+	MaxDistance int
+}
+
+// titleIndex holds the title hashes of recent articles during one Fetch run.
+// Workers claim a title before storing an item, so only one copy of a story
+// is stored even when several sources publish it at once.
+type titleIndex struct {
+	maxDistance int
+
+	mu       sync.Mutex
+	articles []model.Article
+}
+
+func (f *Fetcher) loadTitleIndex(ctx context.Context) (*titleIndex, error) {
+	articles, err := f.articles.RecentTitleHashes(ctx, time.Now().UTC().Add(-f.dedup.Window))
+	if err != nil {
+		return nil, err
+	}
+
+	return &titleIndex{maxDistance: f.dedup.MaxDistance, articles: articles}, nil
+}
+
+// claim reports whether an item may be stored. It returns the article that
+// already covers the story otherwise.
+func (ix *titleIndex) claim(article model.Article) (model.Article, bool) {
+	if article.TitleHash == 0 {
+		return model.Article{}, true
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	for _, existing := range ix.articles {
+		if simhash.Distance(existing.TitleHash, article.TitleHash) <= ix.maxDistance {
+			return existing, false
+		}
+	}
+
+	ix.articles = append(ix.articles, article)
+
+	return model.Article{}, true
+}
+
+// titleHash returns the SimHash of a title, or zero for titles too short to
+// compare.
+func titleHash(title string) uint64 {
+	words := simhash.Words(title)
+	if len(words) < minTitleWords {
+		return 0
+	}
+
+	return simhash.Hash(simhash.Grams(words, titleGramSize))
+}
+
+// resolvedLinks remembers where redirecting links lead, so the items a feed
+// keeps returning are not resolved again on every fetch.
+type resolvedLinks struct {
+	mu      sync.Mutex
+	entries map[string]resolvedLink
+}
+
+type resolvedLink struct {
+	link     string
+	lastSeen time.Time
+}
+
+func newResolvedLinks() *resolvedLinks {
+	return &resolvedLinks{entries: make(map[string]resolvedLink)}
+}
+
+func (r *resolvedLinks) get(raw string, now time.Time) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[raw]
+	if !ok {
+		return "", false
+	}
+
+	entry.lastSeen = now
+	r.entries[raw] = entry
+
+	return entry.link, true
+}
+
+func (r *resolvedLinks) put(raw, link string, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[raw] = resolvedLink{link: link, lastSeen: now}
+}
+
+// prune forgets the links not seen since before.
+func (r *resolvedLinks) prune(before time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for raw, entry := range r.entries {
+		if entry.lastSeen.Before(before) {
+			delete(r.entries, raw)
+		}
+	}
+}
+
+// canonicalLink resolves links of feed proxies and shorteners and strips
+// tracking parameters, so one story is stored under one link. The link is
+// kept unresolved when the redirect cannot be followed. Outcomes are
+// remembered, so each link is resolved once while its feed lists it.
+func (f *Fetcher) canonicalLink(ctx context.Context, link string) string {
+	if !urlutil.IsRedirect(link) {
+		return urlutil.Canonicalize(link)
+	}
+
+	now := time.Now()
+
+	if resolved, ok := f.resolved.get(link, now); ok {
+		return resolved
+	}
+
+	resolved := link
+
+	client, err := f.clients.Client(nil, "")
+	if err == nil {
+		var target string
+
+		target, err = urlutil.ResolveRedirects(ctx, client, link)
+		if err == nil {
+			resolved = target
+		}
+	}
+
+	if err != nil {
+		f.log.Debug("failed to resolve redirect", "link", link, "err", err)
+
+		// Interrupted resolutions are tried again on the next run.
+		if ctx.Err() != nil {
+			return urlutil.Canonicalize(resolved)
+		}
+	}
+
+	resolved = urlutil.Canonicalize(resolved)
+	f.resolved.put(link, resolved, now)
+
+	return resolved
+}
@@ -0,0 +1,76 @@
+package fetcher
+
+import (
+	"context"
+	"news-feed-bot/internal/extract"
+	"news-feed-bot/internal/model"
+	"time"
+)
+
+const (
+	// extractWindow limits extraction to recently stored articles, so the
+	// articles stored before extraction was enabled are not downloaded in bulk.
+	extractWindow = 24 * time.Hour
+	// maxExtractPerRun bounds the pages downloaded for one source per run.
+	maxExtractPerRun = 20
+)
+
+// ContentExtractor downloads an article page and extracts its readable content.
+type ContentExtractor interface {
+	Extract(ctx context.Context, src model.Source, link string) (extract.Content, error)
+}
+
+// extractContent stores the full text of the articles of a source that were
+// not extracted yet. Failures are stored with the article and not retried.
+func (f *Fetcher) extractContent(ctx context.Context, stored model.Source) {
+	if f.extractor == nil {
+		return
+	}
+
+	pending, err := f.articles.PendingExtraction(ctx, stored.ID, time.Now().UTC().Add(-extractWindow), maxExtractPerRun)
+	if err != nil {
+		f.log.Error("failed to list articles pending extraction", "source_id", stored.ID, "err", err)
+		return
+	}
+
+	for _, article := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+
+		started := time.Now()
+
+		content, err := f.extractor.Extract(ctx, stored, article.Link)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			// Left for the next run rather than stored as a failure.
+			return
+		case err != nil:
+			f.log.Warn("failed to extract article content",
+				"source_id", stored.ID,
+				"article_id", article.ID,
+				"err", err,
+			)
+
+			article.ExtractionError = err.Error()
+		default:
+			article.Text = content.Text
+			article.Byline = content.Byline
+			article.LeadImage = content.LeadImage
+			article.WordCount = content.WordCount
+			article.ReadingTime = content.ReadingTime
+			article.ExtractionError = ""
+		}
+
+		if err := f.articles.SaveExtraction(context.WithoutCancel(ctx), article); err != nil {
+			f.log.Error("failed to save extracted content", "article_id", article.ID, "err", err)
+			return
+		}
+
+		f.log.Debug("extracted article content",
+			"article_id", article.ID,
+			"words", article.WordCount,
+			"duration", time.Since(started),
+		)
+	}
+}
@@ -2,8 +2,13 @@ package fetcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"news-feed-bot/internal/extract"
+	"news-feed-bot/internal/filter"
+	"news-feed-bot/internal/lang"
 	"news-feed-bot/internal/model"
 	"news-feed-bot/internal/source"
 	"strings"
@@ -12,11 +17,27 @@ import (
 )
 
 type ArticleStorage interface {
-	Store(ctx context.Context, article model.Article) error
+	// Store saves the article and reports whether it was not stored before.
+	Store(ctx context.Context, article model.Article) (bool, error)
+	// RecentTitleHashes returns the link and title hash of articles stored
+	// since the given time.
+	RecentTitleHashes(ctx context.Context, since time.Time) ([]model.Article, error)
+	PendingExtraction(ctx context.Context, sourceID int64, since time.Time, limit uint64) ([]model.Article, error)
+	SaveExtraction(ctx context.Context, article model.Article) error
+}
+
+// FilterRuleProvider returns the filter rules stored in the database.
+type FilterRuleProvider interface {
+	Rules(ctx context.Context) ([]model.FilterRule, error)
 }
 
 type SourceProvider interface {
 	Sources(ctx context.Context) ([]model.Source, error)
+	UpdateCacheValidators(ctx context.Context, id int64, etag string, lastModified string) error
+	UpdateSchedule(ctx context.Context, id int64, interval time.Duration, nextFetchAt time.Time) error
+	RecordFetchSuccess(ctx context.Context, id int64, at time.Time, hasNewItems bool) error
+	RecordFetchFailure(ctx context.Context, id int64, at time.Time, fetchErr string) (int, error)
+	PauseSource(ctx context.Context, id int64, reason string) error
 }
 
 type Source interface {
@@ -25,36 +46,91 @@ type Source interface {
 	Fetch(ctx context.Context) ([]model.Item, error)
 }
 
+// HTTPClients hands out clients configured with per-source HTTP settings.
+type HTTPClients interface {
+	Client(settings *model.HTTPSettings, feedURL string) (*http.Client, error)
+}
+
+// CachingSource is implemented by sources that make conditional HTTP requests.
+type CachingSource interface {
+	CacheValidators() (etag string, lastModified string)
+}
+
 type Fetcher struct {
 	articles       ArticleStorage
 	sources        SourceProvider
-	fetchInterval  time.Duration
+	filterRules    FilterRuleProvider
+	kinds          *source.Registry
+	clients        HTTPClients
+	extractor      ContentExtractor
+	schedule       Schedule
+	health         HealthPolicy
+	retry          RetryPolicy
+	dedup          DedupPolicy
+	resolved       *resolvedLinks
+	workers        int
 	filterKeywords []string
+	languages      []string
 	log            *slog.Logger
 }
 
+// fetchRun holds the state shared by the workers of one Fetch run.
+type fetchRun struct {
+	titles  *titleIndex
+	filters *filter.Engine
+}
+
+// fetchJob is a due source waiting for a worker.
+type fetchJob struct {
+	stored   model.Source
+	feed     Source
+	queuedAt time.Time
+}
+
 func New(
 	articleStorage ArticleStorage,
 	sourceProvider SourceProvider,
-	fetchInterval time.Duration,
+	filterRules FilterRuleProvider,
+	kinds *source.Registry,
+	clients HTTPClients,
+	extractor ContentExtractor,
+	schedule Schedule,
+	health HealthPolicy,
+	retry RetryPolicy,
+	dedup DedupPolicy,
+	workers int,
 	filterKeywords []string,
+	languages []string,
 	log *slog.Logger,
 ) *Fetcher {
 	return &Fetcher{
 		articles:       articleStorage,
 		sources:        sourceProvider,
+		filterRules:    filterRules,
+		kinds:          kinds,
+		clients:        clients,
+		extractor:      extractor,
 		log:            log,
-		fetchInterval:  fetchInterval,
+		schedule:       schedule,
+		health:         health,
+		retry:          retry,
+		dedup:          dedup,
+		resolved:       newResolvedLinks(),
+		workers:        max(workers, 1),
 		filterKeywords: filterKeywords,
+		languages:      languages,
 	}
 }
 
+// Start fetches due sources on every tick until ctx is cancelled. When ctx is
+// cancelled, no more sources are fetched, but items already downloaded are
+// stored before Start returns.
 func (f *Fetcher) Start(ctx context.Context) error {
 	const op = "fetcher.Start"
 
 	f.log.Info("fetcher was started successfully")
 
-	ticker := time.NewTicker(f.fetchInterval)
+	ticker := time.NewTicker(f.schedule.Tick)
 	defer ticker.Stop()
 
 	if err := f.Fetch(ctx); err != nil {
@@ -67,7 +143,7 @@ func (f *Fetcher) Start(ctx context.Context) error {
 			return fmt.Errorf("%s: %w", op, ctx.Err())
 		case <-ticker.C:
 			if err := f.Fetch(ctx); err != nil {
-				return err
+				return fmt.Errorf("%s: %w", op, err)
 			}
 		}
 	}
@@ -76,71 +152,304 @@ func (f *Fetcher) Start(ctx context.Context) error {
 func (f *Fetcher) Fetch(ctx context.Context) error {
 	const op = "fetcher.Fetch"
 
-	f.log.Info("fetching sources")
-
 	sources, err := f.sources.Sources(ctx)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	var wg sync.WaitGroup
+	titles, err := f.loadTitleIndex(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Rules are reloaded on every run so changes apply without a restart.
+	filters, err := f.loadFilters(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
-	for _, src := range sources {
-		wg.Add(1)
+	run := &fetchRun{titles: titles, filters: filters}
 
-		rssSource := source.New(src)
+	f.resolved.prune(time.Now().Add(-resolvedLinkTTL))
 
-		go func(source Source) {
+	var (
+		wg      sync.WaitGroup
+		jobs    = make(chan fetchJob)
+		now     = time.Now().UTC()
+		started = time.Now()
+		due     int
+	)
+
+	for i := 0; i < f.workers; i++ {
+		wg.Add(1)
+
+		go func() {
 			defer wg.Done()
 
-			items, err := source.Fetch(ctx)
-			if err != nil {
-				f.log.Error(err.Error())
-				return
+			for job := range jobs {
+				f.runJob(ctx, job, run)
 			}
+		}()
+	}
 
-			if err := f.processItems(ctx, source, items); err != nil {
-				f.log.Error(err.Error())
-				return
-			}
-		}(rssSource)
+queue:
+	for _, src := range sources {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if src.Health.Paused || !f.schedule.Due(src, now) {
+			continue
+		}
+
+		due++
+
+		feedSource, err := f.kinds.New(src)
+		if err != nil {
+			f.log.Error("failed to build source", "source_id", src.ID, "err", err)
+			f.recordFailure(ctx, src, err)
+			f.reschedule(ctx, src, nil, 0)
+			continue
+		}
+
+		select {
+		case jobs <- fetchJob{stored: src, feed: feedSource, queuedAt: time.Now()}:
+		case <-ctx.Done():
+			break queue
+		}
 	}
 
+	close(jobs)
 	wg.Wait()
 
+	if due > 0 {
+		f.log.Info("fetched due sources",
+			"due", due,
+			"total", len(sources),
+			"workers", f.workers,
+			"duration", time.Since(started),
+		)
+	}
+
 	return nil
 }
 
-func (f *Fetcher) processItems(ctx context.Context, source Source, items []model.Item) error {
+func (f *Fetcher) runJob(ctx context.Context, job fetchJob, run *fetchRun) {
+	started := time.Now()
+
+	f.fetchSource(ctx, job.stored, job.feed, run)
+
+	f.log.Debug("fetched source",
+		"source_id", job.stored.ID,
+		"queued", started.Sub(job.queuedAt),
+		"duration", time.Since(started),
+	)
+}
+
+// fetchSource fetches a single source, stores its new items and records
+// the outcome for scheduling and health tracking. Once the feed is
+// downloaded, its outcome is recorded even if ctx is cancelled meanwhile.
+func (f *Fetcher) fetchSource(ctx context.Context, stored model.Source, feed Source, run *fetchRun) {
+	var (
+		dates    []time.Time
+		newItems int
+		storeCtx = context.WithoutCancel(ctx)
+	)
+
+	items, err := f.fetchWithRetry(ctx, stored, feed)
+	if err != nil && ctx.Err() != nil {
+		// Interrupted by shutdown: the source is still due on the next start.
+		f.log.Debug("fetch interrupted", "source_id", stored.ID, "err", err)
+		return
+	}
+
+	defer func() {
+		f.reschedule(storeCtx, stored, dates, newItems)
+	}()
+
+	switch {
+	case errors.Is(err, source.ErrNotModified):
+		f.log.Debug("source not modified", "source_id", stored.ID)
+		f.recordSuccess(storeCtx, stored, 0)
+		return
+	case err != nil && isPermanent(err):
+		f.log.Error("permanent fetch failure", "source_id", stored.ID, "err", err)
+		f.recordFailure(storeCtx, stored, err)
+		return
+	case err != nil:
+		f.log.Warn("transient fetch failure", "source_id", stored.ID, "err", err)
+		f.recordFailure(storeCtx, stored, err)
+		return
+	}
+
 	for _, item := range items {
+		dates = append(dates, item.Date)
+	}
+
+	newItems, err = f.processItems(ctx, stored, items, run)
+	if err != nil {
+		f.log.Error(err.Error())
+		return
+	}
+
+	// Validators are only saved once the items are stored, so a failed
+	// run is retried with a full download.
+	if err := f.saveCacheValidators(storeCtx, stored, feed); err != nil {
+		f.log.Error(err.Error())
+	}
+
+	f.recordSuccess(storeCtx, stored, newItems)
+
+	f.extractContent(ctx, stored)
+}
+
+// reschedule stores the next fetch time of a source. It runs after failed
+// fetches too, so a broken source waits for its interval like any other.
+func (f *Fetcher) reschedule(ctx context.Context, stored model.Source, dates []time.Time, newItems int) {
+	interval, next := f.schedule.Next(stored, dates, newItems, time.Now().UTC())
+
+	switch {
+	case !stored.AutoInterval:
+		// Keep a zero interval as is so the source follows the default.
+		interval = stored.FetchInterval
+	case interval != stored.FetchInterval:
+		f.log.Debug("adjusted fetch interval",
+			"source_id", stored.ID,
+			"from", stored.FetchInterval,
+			"to", interval,
+			"new_items", newItems,
+		)
+	}
+
+	if err := f.sources.UpdateSchedule(ctx, stored.ID, interval, next); err != nil {
+		f.log.Error("failed to reschedule source", "source_id", stored.ID, "err", err)
+	}
+}
+
+func (f *Fetcher) saveCacheValidators(ctx context.Context, stored model.Source, feed Source) error {
+	cachingSource, ok := feed.(CachingSource)
+	if !ok {
+		return nil
+	}
+
+	etag, lastModified := cachingSource.CacheValidators()
+	if etag == stored.ETag && lastModified == stored.LastModified {
+		return nil
+	}
+
+	return f.sources.UpdateCacheValidators(ctx, stored.ID, etag, lastModified)
+}
+
+// processItems stores the items that pass the filters and are not copies of
+// a recent article, and returns how many of them were new. It stops with
+// ctx.Err() when ctx is cancelled, between items.
+func (f *Fetcher) processItems(ctx context.Context, source model.Source, items []model.Item, run *fetchRun) (int, error) {
+	var stored int
+
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return stored, err
+		}
+
 		item.Date = item.Date.UTC()
 
-		if f.filterItem(item) {
+		if decision := run.filters.Check(source.ID, item); !decision.Keep {
+			f.log.Debug("filtered out item",
+				"source_id", source.ID,
+				"link", item.Link,
+				"reason", decision.Reason(),
+			)
+
 			continue
 		}
 
-		if err := f.articles.Store(ctx, model.Article{
-			SourceID:    source.ID(),
+		language := lang.Detect(item.Title + "\n" + item.Summary)
+		if !f.languageAllowed(source, language) {
+			f.log.Debug("filtered out item",
+				"source_id", source.ID,
+				"link", item.Link,
+				"reason", "language "+language+" is not allowed",
+			)
+
+			continue
+		}
+
+		article := model.Article{
+			SourceID:    source.ID,
 			Title:       item.Title,
-			Link:        item.Link,
+			Link:        f.canonicalLink(ctx, item.Link),
 			Summary:     item.Summary,
+			SummaryText: extract.PlainText(item.Summary),
 			PublishedAt: item.Date,
-		}); err != nil {
-			return err
+			TitleHash:   titleHash(item.Title),
+			Language:    language,
+		}
+
+		if existing, ok := run.titles.claim(article); !ok {
+			// Seeing an already stored article again is not worth a log line.
+			if existing.Link != article.Link {
+				f.log.Debug("skipped near-duplicate item",
+					"source_id", article.SourceID,
+					"link", article.Link,
+					"duplicate_of", existing.Link,
+					"duplicate_source_id", existing.SourceID,
+				)
+			}
+
+			continue
+		}
+
+		inserted, err := f.articles.Store(context.WithoutCancel(ctx), article)
+		if err != nil {
+			return stored, err
+		}
+
+		if inserted {
+			stored++
 		}
 	}
 
-	return nil
+	return stored, nil
 }
 
-func (f *Fetcher) filterItem(item model.Item) bool {
-	for _, keyword := range f.filterKeywords {
-		titleContainsKeyword := strings.Contains(strings.ToLower(item.Title), keyword)
+// languageAllowed checks a detected language against the languages of the
+// source, or the global ones if the source has none. Items whose language
+// could not be detected are let through.
+func (f *Fetcher) languageAllowed(source model.Source, language string) bool {
+	allowed := source.Languages
+	if len(allowed) == 0 {
+		allowed = f.languages
+	}
+
+	if len(allowed) == 0 || language == "" {
+		return true
+	}
 
-		if titleContainsKeyword {
+	for _, l := range allowed {
+		if strings.EqualFold(l, language) {
 			return true
 		}
 	}
 
 	return false
 }
+
+// loadFilters builds the filter engine from the stored rules and the
+// keywords of the config, which act as global exclude rules. Invalid rules
+// are skipped so one bad pattern does not stop fetching.
+func (f *Fetcher) loadFilters(ctx context.Context) (*filter.Engine, error) {
+	rules, err := f.filterRules.Rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := filter.New()
+
+	for _, rule := range append(filter.KeywordRules(f.filterKeywords), rules...) {
+		if err := engine.Add(rule); err != nil {
+			f.log.Error("skipping invalid filter rule", "rule", filter.Describe(rule), "err", err)
+		}
+	}
+
+	return engine, nil
+}
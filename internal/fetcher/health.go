@@ -0,0 +1,69 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"news-feed-bot/internal/model"
+	"time"
+)
+
+// HealthPolicy decides when a source is paused automatically.
+// Zero values disable the corresponding check.
+type HealthPolicy struct {
+	// MaxFailures is the number of consecutive failed fetches after which
+	// a source is paused.
+	MaxFailures int
+	// StaleAfter pauses a source that has not produced a new item for this long.
+	StaleAfter time.Duration
+}
+
+func (f *Fetcher) recordSuccess(ctx context.Context, stored model.Source, newItems int) {
+	now := time.Now().UTC()
+
+	if err := f.sources.RecordFetchSuccess(ctx, stored.ID, now, newItems > 0); err != nil {
+		f.log.Error("failed to record fetch success", "source_id", stored.ID, "err", err)
+		return
+	}
+
+	if newItems > 0 || f.health.StaleAfter <= 0 {
+		return
+	}
+
+	// Sources that never produced an item are measured from when they were added.
+	lastNewItem := stored.Health.LastNewItemAt
+	if lastNewItem.IsZero() {
+		lastNewItem = stored.CreatedAt
+	}
+
+	if now.Sub(lastNewItem) < f.health.StaleAfter {
+		return
+	}
+
+	f.pause(ctx, stored, fmt.Sprintf(
+		"no new items since %s",
+		lastNewItem.Format(time.DateTime),
+	))
+}
+
+func (f *Fetcher) recordFailure(ctx context.Context, stored model.Source, fetchErr error) {
+	failures, err := f.sources.RecordFetchFailure(ctx, stored.ID, time.Now().UTC(), fetchErr.Error())
+	if err != nil {
+		f.log.Error("failed to record fetch failure", "source_id", stored.ID, "err", err)
+		return
+	}
+
+	if f.health.MaxFailures <= 0 || failures < f.health.MaxFailures {
+		return
+	}
+
+	f.pause(ctx, stored, fmt.Sprintf("%d consecutive failed fetches", failures))
+}
+
+func (f *Fetcher) pause(ctx context.Context, stored model.Source, reason string) {
+	if err := f.sources.PauseSource(ctx, stored.ID, reason); err != nil {
+		f.log.Error("failed to pause source", "source_id", stored.ID, "err", err)
+		return
+	}
+
+	f.log.Warn("source paused", "source_id", stored.ID, "name", stored.Name, "reason", reason)
+}
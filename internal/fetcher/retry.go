@@ -0,0 +1,97 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"news-feed-bot/internal/httpclient"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/source"
+	"time"
+)
+
+// RetryPolicy controls how often a failed fetch is repeated within one run.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; values below 2 disable retries.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// backoff returns the delay before the given retry, counted from 1. It grows
+// exponentially up to MaxBackoff, with half of it randomized so sources that
+// failed together do not retry together.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, p.MaxBackoff)
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+
+	return half + rand.N(half+1)
+}
+
+// isPermanent reports whether repeating a failed fetch cannot help: the
+// source does not exist, refuses access or does not serve a parseable feed.
+func isPermanent(err error) bool {
+	var statusErr *httpclient.StatusError
+	if errors.As(err, &statusErr) {
+		return !statusErr.Temporary()
+	}
+
+	return errors.Is(err, source.ErrParse)
+}
+
+// fetchWithRetry fetches feed, repeating transient failures according to the
+// retry policy. A Retry-After delay requested by the server is honoured; if
+// it is longer than the maximum backoff the source is left for its next run.
+func (f *Fetcher) fetchWithRetry(ctx context.Context, stored model.Source, feed Source) ([]model.Item, error) {
+	for attempt := 1; ; attempt++ {
+		items, err := feed.Fetch(ctx)
+		if err == nil || errors.Is(err, source.ErrNotModified) || isPermanent(err) || ctx.Err() != nil {
+			return items, err
+		}
+
+		if attempt >= f.retry.MaxAttempts {
+			return nil, err
+		}
+
+		delay := f.retry.backoff(attempt)
+
+		var statusErr *httpclient.StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > delay {
+			if statusErr.RetryAfter > f.retry.MaxBackoff {
+				f.log.Info("not retrying fetch before the requested delay",
+					"source_id", stored.ID,
+					"retry_after", statusErr.RetryAfter,
+				)
+
+				return nil, err
+			}
+
+			delay = statusErr.RetryAfter
+		}
+
+		f.log.Info("retrying fetch after transient failure",
+			"source_id", stored.ID,
+			"attempt", attempt,
+			"delay", delay,
+			"err", err,
+		)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+	}
+}
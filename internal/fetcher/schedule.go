@@ -0,0 +1,97 @@
+package fetcher
+
+import (
+	"news-feed-bot/internal/model"
+	"sort"
+	"time"
+)
+
+// Schedule decides which sources are due and when they are fetched next.
+type Schedule struct {
+	// Tick is how often the fetcher checks for due sources.
+	Tick time.Duration
+	// DefaultInterval applies to sources without an interval of their own
+	// and is the starting point for sources in auto mode.
+	DefaultInterval time.Duration
+	// MinInterval and MaxInterval bound the intervals learned in auto mode.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+}
+
+func (s Schedule) Due(src model.Source, now time.Time) bool {
+	return !now.Before(src.NextFetchAt)
+}
+
+// Next returns the interval to keep for src and the time of its next fetch.
+// dates are the publication times of the items returned by the feed and
+// newItems is how many of them were not stored before.
+func (s Schedule) Next(src model.Source, dates []time.Time, newItems int, now time.Time) (time.Duration, time.Time) {
+	interval := src.FetchInterval
+	if interval <= 0 {
+		interval = s.DefaultInterval
+	}
+
+	if src.AutoInterval {
+		interval = s.adapt(interval, dates, newItems)
+	}
+
+	return interval, now.Add(interval)
+}
+
+// adapt polls about twice per typical gap between publications when the
+// feed yields new items and backs off by half when it stays quiet.
+func (s Schedule) adapt(interval time.Duration, dates []time.Time, newItems int) time.Duration {
+	if newItems == 0 {
+		return s.clamp(interval * 3 / 2)
+	}
+
+	if gap, ok := medianGap(dates); ok {
+		return s.clamp(gap / 2)
+	}
+
+	return s.clamp(interval * 2 / 3)
+}
+
+func (s Schedule) clamp(d time.Duration) time.Duration {
+	if d < s.MinInterval {
+		return s.MinInterval
+	}
+	if d > s.MaxInterval {
+		return s.MaxInterval
+	}
+
+	return d
+}
+
+// medianGap returns the median time between consecutive publication dates.
+// Items without a date are ignored.
+func medianGap(dates []time.Time) (time.Duration, bool) {
+	var sorted []time.Time
+
+	for _, d := range dates {
+		if !d.IsZero() {
+			sorted = append(sorted, d)
+		}
+	}
+
+	if len(sorted) < 2 {
+		return 0, false
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	gaps := make([]time.Duration, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		if gap := sorted[i].Sub(sorted[i-1]); gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+
+	if len(gaps) == 0 {
+		return 0, false
+	}
+
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+
+	return gaps[len(gaps)/2], true
+}
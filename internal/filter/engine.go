@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"news-feed-bot/internal/model"
+	"strings"
+)
+
+// Engine decides which items are kept. An item is dropped when an exclude
+// rule matches it, or when include rules apply to its source and none of
+// them matches. Global rules apply to every source.
+type Engine struct {
+	global   []*Rule
+	bySource map[int64][]*Rule
+}
+
+// Decision is the outcome of checking an item. Rule is the exclude rule that
+// dropped the item, or nil if it was kept or matched no include rule.
+type Decision struct {
+	Keep bool
+	Rule *model.FilterRule
+}
+
+func (d Decision) Reason() string {
+	switch {
+	case d.Keep:
+		return "kept"
+	case d.Rule != nil:
+		return "excluded by " + Describe(*d.Rule)
+	}
+
+	return "no include rule matched"
+}
+
+func New() *Engine {
+	return &Engine{bySource: make(map[int64][]*Rule)}
+}
+
+// Add compiles r and adds it to the engine.
+func (e *Engine) Add(r model.FilterRule) error {
+	const op = "filter.Engine.Add"
+
+	rule, err := Compile(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if r.SourceID == 0 {
+		e.global = append(e.global, rule)
+		return nil
+	}
+
+	e.bySource[r.SourceID] = append(e.bySource[r.SourceID], rule)
+
+	return nil
+}
+
+func (e *Engine) Check(sourceID int64, item model.Item) Decision {
+	var (
+		hasInclude bool
+		included   bool
+	)
+
+	for _, rules := range [][]*Rule{e.global, e.bySource[sourceID]} {
+		for _, rule := range rules {
+			switch rule.Action {
+			case ActionExclude:
+				if rule.Matches(item) {
+					return Decision{Rule: &rule.FilterRule}
+				}
+			case ActionInclude:
+				hasInclude = true
+				included = included || rule.Matches(item)
+			}
+		}
+	}
+
+	if hasInclude && !included {
+		return Decision{}
+	}
+
+	return Decision{Keep: true}
+}
+
+func linkDomain(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
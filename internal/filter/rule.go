@@ -0,0 +1,211 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"news-feed-bot/internal/model"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	ActionInclude = "include"
+	ActionExclude = "exclude"
+)
+
+const (
+	FieldTitle    = "title"
+	FieldSummary  = "summary"
+	FieldCategory = "category"
+	FieldDomain   = "domain"
+	FieldAuthor   = "author"
+	// FieldAny matches the title, summary and categories.
+	FieldAny = "any"
+)
+
+const (
+	MatchContains = "contains"
+	MatchWord     = "word"
+	MatchRegex    = "regex"
+)
+
+var ErrInvalidRule = errors.New("invalid filter rule")
+
+var (
+	Actions = []string{ActionInclude, ActionExclude}
+	Fields  = []string{FieldTitle, FieldSummary, FieldCategory, FieldDomain, FieldAuthor, FieldAny}
+	Matches = []string{MatchContains, MatchWord, MatchRegex}
+)
+
+// Rule is a filter rule ready to be matched against items.
+type Rule struct {
+	model.FilterRule
+	match func(text string) bool
+}
+
+// Compile validates r and prepares its pattern. An empty match defaults to
+// contains and an empty field to any.
+func Compile(r model.FilterRule) (*Rule, error) {
+	const op = "filter.Compile"
+
+	if r.Match == "" {
+		r.Match = MatchContains
+	}
+	if r.Field == "" {
+		r.Field = FieldAny
+	}
+
+	switch {
+	case !contains(Actions, r.Action):
+		return nil, fmt.Errorf("%s: %w: unknown action %q", op, ErrInvalidRule, r.Action)
+	case !contains(Fields, r.Field):
+		return nil, fmt.Errorf("%s: %w: unknown field %q", op, ErrInvalidRule, r.Field)
+	case strings.TrimSpace(r.Pattern) == "":
+		return nil, fmt.Errorf("%s: %w: empty pattern", op, ErrInvalidRule)
+	}
+
+	rule := &Rule{FilterRule: r}
+	pattern := strings.ToLower(r.Pattern)
+
+	switch r.Match {
+	case MatchContains:
+		rule.match = func(text string) bool {
+			return strings.Contains(strings.ToLower(text), pattern)
+		}
+	case MatchWord:
+		rule.match = func(text string) bool {
+			return containsWord(strings.ToLower(text), pattern)
+		}
+	case MatchRegex:
+		re, err := regexp.Compile("(?i)" + r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", op, ErrInvalidRule, err)
+		}
+
+		rule.match = re.MatchString
+	default:
+		return nil, fmt.Errorf("%s: %w: unknown match %q", op, ErrInvalidRule, r.Match)
+	}
+
+	return rule, nil
+}
+
+// Matches reports whether the rule matches any of the values of its field.
+func (r *Rule) Matches(item model.Item) bool {
+	for _, value := range fieldValues(item, r.Field) {
+		if value != "" && r.match(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Describe renders a rule for logs and bot replies, e.g.
+// `#3 exclude title word "crypto" (source 5)`.
+func Describe(r model.FilterRule) string {
+	var b strings.Builder
+
+	if r.ID != 0 {
+		fmt.Fprintf(&b, "#%d ", r.ID)
+	}
+
+	fmt.Fprintf(&b, "%s %s %s %q", r.Action, r.Field, r.Match, r.Pattern)
+
+	if r.SourceID != 0 {
+		fmt.Fprintf(&b, " (source %d)", r.SourceID)
+	}
+
+	return b.String()
+}
+
+// ArticleItem rebuilds the feed item of a stored article. Categories and
+// authors are not stored, so rules on them never match it.
+func ArticleItem(article model.Article) model.Item {
+	return model.Item{
+		Title:   article.Title,
+		Link:    article.Link,
+		Date:    article.PublishedAt,
+		Summary: article.Summary,
+	}
+}
+
+// KeywordRules turns the keywords of the config into global exclude rules on
+// the title.
+func KeywordRules(keywords []string) []model.FilterRule {
+	var rules []model.FilterRule
+
+	for _, keyword := range keywords {
+		if strings.TrimSpace(keyword) == "" {
+			continue
+		}
+
+		rules = append(rules, model.FilterRule{
+			Action:  ActionExclude,
+			Field:   FieldTitle,
+			Match:   MatchContains,
+			Pattern: keyword,
+		})
+	}
+
+	return rules
+}
+
+func fieldValues(item model.Item, field string) []string {
+	switch field {
+	case FieldTitle:
+		return []string{item.Title}
+	case FieldSummary:
+		return []string{item.Summary}
+	case FieldCategory:
+		return item.Categories
+	case FieldDomain:
+		return []string{linkDomain(item.Link)}
+	case FieldAuthor:
+		return []string{item.Author}
+	}
+
+	return append([]string{item.Title, item.Summary}, item.Categories...)
+}
+
+// containsWord reports whether word occurs in text with no letters or
+// digits right before or after it. Both are expected to be lowercased.
+func containsWord(text, word string) bool {
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return false
+		}
+
+		start := offset + i
+		end := start + len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package filter
+
+import (
+	"news-feed-bot/internal/model"
+	"testing"
+)
+
+func TestContainsWord(t *testing.T) {
+	tests := []struct {
+		text string
+		word string
+		want bool
+	}{
+		{"crypto news", "crypto", true},
+		{"news about crypto", "crypto", true},
+		{"crypto", "crypto", true},
+		{"cryptocurrency news", "crypto", false},
+		{"anticrypto news", "crypto", false},
+		{"crypto2 news", "crypto", false},
+		{"crypto, news", "crypto", true},
+		{"(crypto)", "crypto", true},
+		{"crypto-news", "crypto", true},
+		{"cryptocurrency and crypto", "crypto", true},
+		{"ai and the ai-act", "ai", true},
+		{"said", "ai", false},
+		{"новости крипто", "крипто", true},
+		{"криптовалюта", "крипто", false},
+		{"café society", "caf", false},
+		{"two words here", "two words", true},
+		{"", "crypto", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text+"/"+tt.word, func(t *testing.T) {
+			if got := containsWord(tt.text, tt.word); got != tt.want {
+				t.Errorf("containsWord(%q, %q) = %v, want %v", tt.text, tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	item := model.Item{
+		Title:      "Bitcoin Rallies Again",
+		Summary:    "<p>Markets moved.</p>",
+		Link:       "https://www.example.com/markets/1",
+		Categories: []string{"Finance"},
+		Author:     "Jane Doe",
+	}
+
+	tests := []struct {
+		name string
+		rule model.FilterRule
+		want bool
+	}{
+		{"contains ignores case", model.FilterRule{Field: FieldTitle, Pattern: "bitcoin"}, true},
+		{"contains part of a word", model.FilterRule{Field: FieldTitle, Pattern: "rall"}, true},
+		{"word needs a whole word", model.FilterRule{Field: FieldTitle, Match: MatchWord, Pattern: "rall"}, false},
+		{"word ignores case", model.FilterRule{Field: FieldTitle, Match: MatchWord, Pattern: "RALLIES"}, true},
+		{"regex ignores case", model.FilterRule{Field: FieldTitle, Match: MatchRegex, Pattern: `^bitcoin\b`}, true},
+		{"summary", model.FilterRule{Field: FieldSummary, Pattern: "markets"}, true},
+		{"category", model.FilterRule{Field: FieldCategory, Match: MatchWord, Pattern: "finance"}, true},
+		{"author", model.FilterRule{Field: FieldAuthor, Pattern: "doe"}, true},
+		{"domain", model.FilterRule{Field: FieldDomain, Pattern: "example.com"}, true},
+		{"any field", model.FilterRule{Pattern: "finance"}, true},
+		{"any field skips the author", model.FilterRule{Pattern: "jane"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Action = ActionExclude
+
+			rule, err := Compile(tt.rule)
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+
+			if got := rule.Matches(item); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"news-feed-bot/internal/model"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults apply to requests of sources without settings of their own.
+type Defaults struct {
+	UserAgent string
+	Timeout   time.Duration
+}
+
+// Pool hands out HTTP clients configured with per-source settings. Clients
+// are cached by their settings so connections are reused between fetches,
+// and all of them share the per-host limits.
+type Pool struct {
+	defaults Defaults
+	limiter  *hostLimiter
+
+	mu      sync.Mutex
+	clients map[string]*http.Client
+}
+
+func NewPool(defaults Defaults, limits HostLimits) *Pool {
+	return &Pool{
+		defaults: defaults,
+		limiter:  newHostLimiter(limits),
+		clients:  make(map[string]*http.Client),
+	}
+}
+
+// Client returns the client for settings, which may be nil. The headers and
+// credentials of settings are only sent to the host of feedURL, so they do
+// not leak to other hosts through redirects or article links.
+func (p *Pool) Client(settings *model.HTTPSettings, feedURL string) (*http.Client, error) {
+	const op = "httpclient.Pool.Client"
+
+	if settings == nil {
+		settings = &model.HTTPSettings{}
+	}
+
+	var host string
+	if feedURL != "" {
+		parsed, err := url.Parse(feedURL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		host = parsed.Hostname()
+	}
+
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key := host + " " + string(raw)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if client, ok := p.clients[key]; ok {
+		return client, nil
+	}
+
+	client, err := p.newClient(*settings, host)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	p.clients[key] = client
+
+	return client, nil
+}
+
+func (p *Pool) newClient(settings model.HTTPSettings, host string) (*http.Client, error) {
+	timeout := p.defaults.Timeout
+	if settings.Timeout != "" {
+		parsed, err := time.ParseDuration(settings.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("timeout: %w", err)
+		}
+
+		timeout = parsed
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if settings.ProxyURL != "" {
+		proxyURL, err := url.Parse(settings.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("proxy url: %w", err)
+		}
+
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	userAgent := p.defaults.UserAgent
+	if settings.UserAgent != "" {
+		userAgent = settings.UserAgent
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &settingsTransport{
+			base: &limitedTransport{
+				base:    transport,
+				limiter: p.limiter,
+			},
+			settings:  settings,
+			host:      host,
+			userAgent: userAgent,
+		},
+	}, nil
+}
+
+// settingsTransport adds the configured headers and credentials to the
+// requests made to host, and the user agent to every request.
+type settingsTransport struct {
+	base      http.RoundTripper
+	settings  model.HTTPSettings
+	host      string
+	userAgent string
+}
+
+func (t *settingsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	// Redirects come through here as new requests, so a redirect to
+	// another host goes out without the headers and credentials.
+	if t.host != "" && strings.EqualFold(req.URL.Hostname(), t.host) {
+		for name, value := range t.settings.Headers {
+			req.Header.Set(name, value)
+		}
+
+		switch {
+		case t.settings.BearerToken != "":
+			req.Header.Set("Authorization", "Bearer "+t.settings.BearerToken)
+		case t.settings.BasicUsername != "":
+			req.SetBasicAuth(t.settings.BasicUsername, t.settings.BasicPassword)
+		}
+	}
+
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+
+	return t.base.RoundTrip(req)
+}
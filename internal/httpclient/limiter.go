@@ -0,0 +1,143 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HostLimits bound the load put on a single host by all clients of a Pool.
+// Zero values disable the corresponding limit.
+type HostLimits struct {
+	// Concurrency is the number of requests to a host that may be in flight,
+	// counted until the response body is closed.
+	Concurrency int
+	// RequestsPerSecond spaces out the start of requests to a host.
+	RequestsPerSecond float64
+}
+
+type hostLimiter struct {
+	limits HostLimits
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+type hostState struct {
+	slots chan struct{}
+	next  time.Time
+}
+
+func newHostLimiter(limits HostLimits) *hostLimiter {
+	return &hostLimiter{
+		limits: limits,
+		hosts:  make(map[string]*hostState),
+	}
+}
+
+// acquire blocks until a request to host may start and returns the function
+// that frees its concurrency slot.
+func (l *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	state := l.state(host)
+
+	release := func() {}
+
+	if state.slots != nil {
+		select {
+		case state.slots <- struct{}{}:
+			release = sync.OnceFunc(func() { <-state.slots })
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if wait := l.reserve(state); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	return release, nil
+}
+
+// reserve books the next start time for a request to the host and returns
+// how long the caller has to wait for it.
+func (l *hostLimiter) reserve(state *hostState) time.Duration {
+	if l.limits.RequestsPerSecond <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	start := state.next
+	if start.Before(now) {
+		start = now
+	}
+
+	state.next = start.Add(time.Duration(float64(time.Second) / l.limits.RequestsPerSecond))
+
+	return start.Sub(now)
+}
+
+func (l *hostLimiter) state(host string) *hostState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, ok := l.hosts[host]
+	if !ok {
+		state = &hostState{}
+		if l.limits.Concurrency > 0 {
+			state.slots = make(chan struct{}, l.limits.Concurrency)
+		}
+
+		l.hosts[host] = state
+	}
+
+	return state
+}
+
+// limitedTransport applies the host limits of its pool to every request.
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter *hostLimiter
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.acquire(req.Context(), req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+// releasingBody frees the concurrency slot of its request once closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+
+	return b.ReadCloser.Close()
+}
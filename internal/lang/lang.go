@@ -0,0 +1,133 @@
+// Package lang guesses the language of short texts such as feed item titles
+// and summaries without any external service. Languages are reported as
+// ISO 639-1 codes.
+package lang
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// minLetters is the least amount of letters needed for a guess.
+const minLetters = 12
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// scriptLanguages maps scripts used by a single language to it.
+var scriptLanguages = []struct {
+	script *unicode.RangeTable
+	lang   string
+}{
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Hangul, "ko"},
+	{unicode.Thai, "th"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Armenian, "hy"},
+	{unicode.Georgian, "ka"},
+}
+
+// Detect returns the language of text, or an empty string when the text is
+// too short or too ambiguous to tell. HTML tags are ignored.
+func Detect(text string) string {
+	text = tagPattern.ReplaceAllString(text, " ")
+
+	counts := make(map[*unicode.RangeTable]int)
+	letters := 0
+
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		letters++
+
+		for _, script := range []*unicode.RangeTable{
+			unicode.Latin, unicode.Cyrillic, unicode.Arabic, unicode.Han,
+			unicode.Hiragana, unicode.Katakana,
+		} {
+			if unicode.Is(script, r) {
+				counts[script]++
+			}
+		}
+
+		for _, sl := range scriptLanguages {
+			if unicode.Is(sl.script, r) {
+				counts[sl.script]++
+			}
+		}
+	}
+
+	if letters < minLetters {
+		return ""
+	}
+
+	// Japanese mixes kana with Han, Chinese uses Han alone.
+	if counts[unicode.Hiragana]+counts[unicode.Katakana] > letters/10 {
+		return "ja"
+	}
+
+	dominant := func(script *unicode.RangeTable) bool {
+		return counts[script] > letters/2
+	}
+
+	switch {
+	case dominant(unicode.Han):
+		return "zh"
+	case dominant(unicode.Arabic):
+		return detectArabic(text)
+	case dominant(unicode.Cyrillic):
+		return detectByProfile(text, cyrillicProfiles)
+	case dominant(unicode.Latin):
+		return detectByProfile(text, latinProfiles)
+	}
+
+	for _, sl := range scriptLanguages {
+		if dominant(sl.script) {
+			return sl.lang
+		}
+	}
+
+	return ""
+}
+
+// detectArabic tells Persian from Arabic by the letters Arabic lacks.
+func detectArabic(text string) string {
+	if strings.ContainsAny(text, "پچژگ") {
+		return "fa"
+	}
+
+	return "ar"
+}
+
+// detectByProfile scores text against each profile and returns the best
+// one, provided it is a clear winner.
+func detectByProfile(text string, profiles []profile) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	var (
+		best      string
+		bestScore int
+		runnerUp  int
+	)
+
+	for _, p := range profiles {
+		score := p.score(words)
+
+		switch {
+		case score > bestScore:
+			best, runnerUp, bestScore = p.lang, bestScore, score
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+
+	if bestScore == 0 || bestScore == runnerUp {
+		return ""
+	}
+
+	return best
+}
@@ -0,0 +1,47 @@
+package lang
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english title", "The markets are up after the new report", "en"},
+		{"german title", "Die Regierung will das Gesetz über Steuern ändern", "de"},
+		{"french title", "Le gouvernement présente une réforme pour les retraites", "fr"},
+		{"russian title", "Правительство уже обсуждает новый закон о налогах", "ru"},
+		{"ukrainian title", "Уряд також обговорює новий закон про податки", "uk"},
+		{"greek title", "Η κυβέρνηση ανακοίνωσε νέα μέτρα", "el"},
+		{"persian title", "دولت برنامه جدید را اعلام کرد و گفتگو ادامه دارد", "fa"},
+		{"arabic title", "أعلنت الحكومة عن خطة جديدة للاقتصاد", "ar"},
+		{"japanese title", "東京で新しいプロジェクトが始まりました", "ja"},
+		{"chinese title", "政府宣布新的经济计划以促进发展", "zh"},
+		{"html tags ignored", "<p><a href=\"https://example.com/the-and-of\">Правительство уже обсуждает новый закон</a></p>", "ru"},
+
+		// Too short to tell.
+		{"empty", "", ""},
+		{"short title", "Go 1.22", ""},
+		{"short word title", "Breaking news", ""},
+		{"digits and punctuation", "2024-06-03 12:00:00 — 99%!", ""},
+		{"short title padded with tags", "<div><span>Hello</span></div>", ""},
+
+		// Mixed scripts.
+		{"latin and cyrillic halves", "Apple представила iPhone news", ""},
+		{"latin words in a cyrillic title", "Компания Apple уже представила новый iPhone", "ru"},
+		{"cyrillic words in a latin title", "The band Кино is back with a new album", "en"},
+
+		// No clear winner within a script.
+		{"no stop words", "Quantum supercomputing breakthroughs", ""},
+		{"tied profiles", "Bitcoin Ethereum Dogecoin Solana", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package lang
+
+import "strings"
+
+// letterWeight is how many stop words a letter specific to a language is
+// worth. Such letters are rare in other languages of the same script.
+const letterWeight = 2
+
+// profile describes a language by its most frequent short words and the
+// letters that set it apart from languages written in the same script.
+type profile struct {
+	lang      string
+	stopWords map[string]bool
+	letters   string
+}
+
+func newProfile(lang string, stopWords string, letters string) profile {
+	p := profile{lang: lang, stopWords: make(map[string]bool), letters: letters}
+
+	for _, w := range strings.Fields(stopWords) {
+		p.stopWords[w] = true
+	}
+
+	return p
+}
+
+func (p profile) score(words []string) int {
+	score := 0
+
+	for _, w := range words {
+		if p.stopWords[w] {
+			score++
+		}
+
+		if p.letters != "" && strings.ContainsAny(w, p.letters) {
+			score += letterWeight
+		}
+	}
+
+	return score
+}
+
+var latinProfiles = []profile{
+	newProfile("en", "the and of to in is for on that with as by at from it are was be this an has have will after over new its not but who how what", ""),
+	newProfile("de", "der die das und ist nicht mit von den für auf im dem ein eine zu sich auch bei nach wird des aus wie noch über sind oder", "äöüß"),
+	newProfile("fr", "le la les et des est une un du dans pour pas que qui sur au avec par plus sont ce aux cette été mais ou son", "àâçèêëîïôœùû"),
+	newProfile("es", "el la los las de y en que del se por un una con para es al lo como más su sus pero este esta fue ha", "ñ¿¡"),
+	newProfile("it", "il la di che e è per un una del della in con non le si da sono gli alla nel dei anche come più ha", "ìò"),
+	newProfile("pt", "o a os as de do da dos das e em que um uma para com não no na por se é ao mais como foi pelo", "ãõ"),
+	newProfile("nl", "de het een en van is in op te dat die niet met voor zijn er ook aan bij maar naar wordt nog om", "ĳ"),
+	newProfile("pl", "i w na z się nie do to że jest o jak po od za przez dla czy ale jego są już oraz", "ąęłńśźż"),
+	newProfile("tr", "ve bir bu da de için ile çok olarak daha gibi en ne ama var sonra kadar olan ise", "ğışı"),
+	newProfile("sv", "och att det som en är på för med av till den har inte om ett men vi kan från", "å"),
+}
+
+var cyrillicProfiles = []profile{
+	newProfile("ru", "и в не на что с по это как к из у за о от для он а так но же то уже его года было при также", "ыэё"),
+	newProfile("uk", "і в не на що з у до як та це за від для про він але його року також вже є", "іїєґ"),
+	newProfile("be", "і ў не на што з да як гэта за ад для пра ён але яго года таксама", "ў"),
+	newProfile("bg", "и в на не за да се от с по че е са като това тази този които която ще беше но към след или още при", "ъ"),
+	newProfile("sr", "и у на не за да се од с по је су као то али или још при", "ђјљњћџ"),
+}
@@ -11,24 +11,143 @@ type Item struct {
 	Link       string
 	Date       time.Time
 	Summary    string
+	Author     string
 	SourceName string
 }
 
 type Source struct {
+	ID            int64            `db:"id"`
+	Name          string           `db:"name"`
+	FeedURL       string           `db:"feed_url"`
+	Kind          string           `db:"kind"`
+	Selectors     *ScrapeSelectors `db:"selectors"`
+	HTTP          *HTTPSettings    `db:"http_settings"`
+	ETag          string           `db:"etag"`
+	LastModified  string           `db:"last_modified"`
+	FetchInterval time.Duration    `db:"fetch_interval_seconds"`
+	AutoInterval  bool             `db:"fetch_auto"`
+	NextFetchAt   time.Time        `db:"next_fetch_at"`
+	Languages     []string         `db:"languages"`
+	Priority      float64          `db:"priority"`
+	Group         string           `db:"source_group"`
+	Health        SourceHealth
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// SourceHealth tracks the outcome of recent fetches of a source.
+type SourceHealth struct {
+	LastSuccessAt       time.Time `db:"last_success_at"`
+	LastError           string    `db:"last_error"`
+	LastErrorAt         time.Time `db:"last_error_at"`
+	ConsecutiveFailures int       `db:"consecutive_failures"`
+	LastNewItemAt       time.Time `db:"last_new_item_at"`
+	Paused              bool      `db:"paused"`
+	PausedReason        string    `db:"paused_reason"`
+}
+
+// ScrapeSelectors are the CSS selectors used to pull items out of a web page.
+// Item selects each item container, the rest are matched inside it.
+type ScrapeSelectors struct {
+	Item    string `json:"item"`
+	Title   string `json:"title,omitempty"`
+	Link    string `json:"link,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// HTTPSettings customizes the requests made for a source, both for its feed
+// and for the pages of its articles. Headers and credentials are only sent
+// to the host of the feed.
+type HTTPSettings struct {
+	Headers       map[string]string `json:"headers,omitempty"`
+	BasicUsername string            `json:"basic_username,omitempty"`
+	BasicPassword string            `json:"basic_password,omitempty"`
+	BearerToken   string            `json:"bearer_token,omitempty"`
+	UserAgent     string            `json:"user_agent,omitempty"`
+	ProxyURL      string            `json:"proxy_url,omitempty"`
+	// Timeout is a Go duration such as "20s".
+	Timeout string `json:"timeout,omitempty"`
+}
+
+type Article struct {
+	ID              int64         `db:"id"`
+	SourceID        int64         `db:"source_id"`
+	Title           string        `db:"title"`
+	Link            string        `db:"link"`
+	Summary         string        `db:"summary"`
+	SummaryText     string        `db:"summary_text"`
+	PublishedAt     time.Time     `db:"published_at"`
+	CreatedAt       time.Time     `db:"created_at"`
+	PostedAt        sql.NullTime  `db:"posted_at"`
+	TitleHash       uint64        `db:"title_hash"`
+	Language        string        `db:"language"`
+	Text            string        `db:"content_text"`
+	Byline          string        `db:"byline"`
+	LeadImage       string        `db:"lead_image"`
+	WordCount       int           `db:"word_count"`
+	ReadingTime     time.Duration `db:"reading_time_seconds"`
+	ExtractionError string        `db:"extraction_error"`
+	ExtractedAt     sql.NullTime  `db:"extracted_at"`
+}
+
+type FilterRule struct {
 	ID        int64     `db:"id"`
-	Name      string    `db:"name"`
-	FeedURL   string    `db:"feed_url"`
+	SourceID  int64     `db:"source_id"`
+	Action    string    `db:"action"`
+	Field     string    `db:"field"`
+	Match     string    `db:"match"`
+	Pattern   string    `db:"pattern"`
 	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
 }
 
-type Article struct {
-	ID          int64        `db:"id"`
-	SourceID    int64        `db:"source_id"`
-	Title       string       `db:"title"`
-	Link        string       `db:"link"`
-	Summary     string       `db:"summary"`
-	PublishedAt time.Time    `db:"published_at"`
-	CreatedAt   time.Time    `db:"created_at"`
-	PostedAt    sql.NullTime `db:"posted_at"`
+type Channel struct {
+	ID        int64  `db:"id"`
+	ChatID    int64  `db:"chat_id"`
+	Name      string `db:"name"`
+	Digest    ChannelDigest
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ChannelDigest makes a channel receive one digest a day or a week instead
+// of posts every notification interval. An empty mode turns digests off.
+type ChannelDigest struct {
+	Mode string `db:"digest_mode"`
+	// Time is the time of day the digest is posted at, as "15:04", in the
+	// time zone of the posting schedule.
+	Time    string       `db:"digest_time"`
+	Weekday time.Weekday `db:"digest_weekday"`
+	Size    int          `db:"digest_size"`
+	LastAt  time.Time    `db:"last_digest_at"`
+}
+
+// ChannelRoute sends articles to a channel. Exactly one of SourceID, Group
+// or Pattern is set.
+type ChannelRoute struct {
+	ID        int64     `db:"id"`
+	ChannelID int64     `db:"channel_id"`
+	SourceID  int64     `db:"source_id"`
+	Group     string    `db:"source_group"`
+	Field     string    `db:"field"`
+	Match     string    `db:"match"`
+	Pattern   string    `db:"pattern"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type Subscriber struct {
+	ID        int64     `db:"id"`
+	ChatID    int64     `db:"chat_id"`
+	Username  string    `db:"username"`
+	Active    bool      `db:"active"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Subscription sends the articles of a source or a source group to a
+// subscriber. Exactly one of SourceID or Group is set.
+type Subscription struct {
+	ID           int64     `db:"id"`
+	SubscriberID int64     `db:"subscriber_id"`
+	SourceID     int64     `db:"source_id"`
+	Group        string    `db:"source_group"`
+	CreatedAt    time.Time `db:"created_at"`
 }
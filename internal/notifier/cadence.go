@@ -0,0 +1,104 @@
+package notifier
+
+import (
+	"news-feed-bot/internal/scoring"
+	"time"
+)
+
+// Cadence controls how many articles are posted per tick and how the posts
+// are spread between sources.
+type Cadence struct {
+	PostsPerTick int
+	// SourceSpacing is the minimum time between two posts of the same source.
+	SourceSpacing time.Duration
+	// SourceDailyCap limits the posts of each source within 24 hours.
+	// Zero disables the cap.
+	SourceDailyCap int
+	// CatchUpThreshold is the number of waiting articles from which
+	// CatchUpPostsPerTick are posted per tick instead. Zero disables catch-up.
+	CatchUpThreshold    int
+	CatchUpPostsPerTick int
+	// DrainPostsPerTick is the batch size while the articles accumulated
+	// during quiet hours are posted. Zero uses the regular batch size.
+	DrainPostsPerTick int
+}
+
+// postHistory is what a source has posted recently. Selection updates it as
+// articles are picked, so limits hold within a batch as well.
+type postHistory struct {
+	lastPosted  map[int64]time.Time
+	postedToday map[int64]int
+}
+
+// postsPerTick returns the size of the next batch given the number of
+// articles waiting to be posted.
+func (c Cadence) postsPerTick(backlog int) int {
+	n := max(c.PostsPerTick, 1)
+
+	if c.CatchUpThreshold > 0 && backlog >= c.CatchUpThreshold {
+		n = max(n, c.CatchUpPostsPerTick)
+	}
+
+	return n
+}
+
+// drainPostsPerTick returns the size of the next batch after quiet hours.
+func (c Cadence) drainPostsPerTick(backlog int) int {
+	if c.DrainPostsPerTick > 0 {
+		return c.DrainPostsPerTick
+	}
+
+	return c.postsPerTick(backlog)
+}
+
+// selectArticles picks up to n of the ranked articles. Sources take turns:
+// every round gives each eligible source its best remaining article, so a
+// prolific source can not fill a batch while others wait.
+func (c Cadence) selectArticles(ranked []scoring.Scored, n int, history postHistory, now time.Time) []scoring.Scored {
+	taken := make([]bool, len(ranked))
+
+	var selected []scoring.Scored
+
+	for len(selected) < n {
+		picked := make(map[int64]bool)
+
+		for i, candidate := range ranked {
+			if len(selected) == n {
+				break
+			}
+
+			sourceID := candidate.Article.SourceID
+
+			if taken[i] || picked[sourceID] || !c.allowed(sourceID, history, now) {
+				continue
+			}
+
+			taken[i] = true
+			picked[sourceID] = true
+			selected = append(selected, candidate)
+
+			history.lastPosted[sourceID] = now
+			history.postedToday[sourceID]++
+		}
+
+		if len(picked) == 0 {
+			break
+		}
+	}
+
+	return selected
+}
+
+func (c Cadence) allowed(sourceID int64, history postHistory, now time.Time) bool {
+	if c.SourceSpacing > 0 {
+		if last, ok := history.lastPosted[sourceID]; ok && now.Sub(last) < c.SourceSpacing {
+			return false
+		}
+	}
+
+	if c.SourceDailyCap > 0 && history.postedToday[sourceID] >= c.SourceDailyCap {
+		return false
+	}
+
+	return true
+}
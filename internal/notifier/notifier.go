@@ -2,22 +2,50 @@ package notifier
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/go-shiori/go-readability"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"io"
 	"log/slog"
-	"net/http"
 	"news-feed-bot/internal/botkit/markup"
+	"news-feed-bot/internal/extract"
 	"news-feed-bot/internal/model"
-	"regexp"
+	"news-feed-bot/internal/scoring"
 	"strings"
 	"time"
 )
 
 type ArticleProvider interface {
-	NotPostedArticles(ctx context.Context, since time.Time, limit uint64) ([]model.Article, error)
-	MarkAsPosted(ctx context.Context, id int64) error
+	MarkAsPosted(ctx context.Context, channelID int64, id int64) error
+	LastPostedBySource(ctx context.Context, channelID int64, since time.Time) (map[int64]time.Time, error)
+	PostedCountBySource(ctx context.Context, channelID int64, since time.Time) (map[int64]int, error)
+}
+
+// ArticleRanker orders the articles routed to a channel and not posted there
+// yet, the one to post next first.
+type ArticleRanker interface {
+	Rank(ctx context.Context, channelID int64, now time.Time) ([]scoring.Scored, error)
+	// RankFrom ranks as Rank does, keeping the articles published since
+	// from as candidates.
+	RankFrom(ctx context.Context, channelID int64, from time.Time, now time.Time) ([]scoring.Scored, error)
+}
+
+// PostingSchedule tells whether articles may be posted at now, given the
+// time of the previous check.
+type PostingSchedule interface {
+	OpenSince(since, now time.Time) bool
+}
+
+type ChannelProvider interface {
+	Channels(ctx context.Context) ([]model.Channel, error)
+}
+
+type SourceProvider interface {
+	SourceById(ctx context.Context, id int64) (*model.Source, error)
+}
+
+// ContentExtractor downloads an article page and extracts its readable content.
+type ContentExtractor interface {
+	Extract(ctx context.Context, src model.Source, link string) (extract.Content, error)
 }
 
 type Summarizer interface {
@@ -25,32 +53,52 @@ type Summarizer interface {
 }
 
 type Notifier struct {
-	articles         ArticleProvider
-	summarizer       Summarizer
-	bot              *tgbotapi.BotAPI
-	sendInterval     time.Duration
-	articleRelevance time.Duration
-	channelID        int64
-	log              *slog.Logger
+	articles     ArticleProvider
+	ranker       ArticleRanker
+	channels     ChannelProvider
+	sources      SourceProvider
+	extractor    ContentExtractor
+	summarizer   Summarizer
+	bot          *tgbotapi.BotAPI
+	sendInterval time.Duration
+	cadence      Cadence
+	schedule     PostingSchedule
+	log          *slog.Logger
+
+	// quietSince holds, per channel, when the quiet hours whose articles
+	// are still being drained began. It is lost on restart, after which
+	// only articles within the relevance window are posted.
+	quietSince map[int64]time.Time
+	// lastCheck is when the posting schedule was last checked.
+	lastCheck time.Time
 }
 
 func New(
 	articleProvider ArticleProvider,
+	ranker ArticleRanker,
+	channelProvider ChannelProvider,
+	sourceProvider SourceProvider,
+	extractor ContentExtractor,
 	summarizer Summarizer,
 	bot *tgbotapi.BotAPI,
 	sendInterval time.Duration,
-	articleRelevance time.Duration,
-	channelID int64,
+	cadence Cadence,
+	schedule PostingSchedule,
 	log *slog.Logger,
 ) *Notifier {
 	return &Notifier{
-		articles:         articleProvider,
-		summarizer:       summarizer,
-		bot:              bot,
-		sendInterval:     sendInterval,
-		articleRelevance: articleRelevance,
-		channelID:        channelID,
-		log:              log,
+		articles:     articleProvider,
+		ranker:       ranker,
+		channels:     channelProvider,
+		sources:      sourceProvider,
+		extractor:    extractor,
+		summarizer:   summarizer,
+		bot:          bot,
+		sendInterval: sendInterval,
+		cadence:      cadence,
+		schedule:     schedule,
+		log:          log,
+		quietSince:   make(map[int64]time.Time),
 	}
 }
 
@@ -62,14 +110,14 @@ func (n *Notifier) Start(ctx context.Context) error {
 	ticker := time.NewTicker(n.sendInterval)
 	defer ticker.Stop()
 
-	if err := n.SelectAndSendArticle(ctx); err != nil {
+	if err := n.SelectAndSendArticles(ctx); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	for {
 		select {
 		case <-ticker.C:
-			if err := n.SelectAndSendArticle(ctx); err != nil {
+			if err := n.SelectAndSendArticles(ctx); err != nil {
 				return fmt.Errorf("%s: %w", op, err)
 			}
 		case <-ctx.Done():
@@ -78,55 +126,209 @@ func (n *Notifier) Start(ctx context.Context) error {
 	}
 }
 
-func (n *Notifier) SelectAndSendArticle(ctx context.Context) error {
-	const op = "notifier.SelectAndSendArticle"
-
-	n.log.Info("selecting and sending article")
+// SelectAndSendArticles posts the next batch of articles to every channel
+// not in digest mode. Each channel has its own queue; the cadence applies
+// per channel. A failing channel is logged and does not hold back the others.
+// Outside the posting schedule nothing is posted and articles accumulate.
+func (n *Notifier) SelectAndSendArticles(ctx context.Context) error {
+	const op = "notifier.SelectAndSendArticles"
 
-	topArticles, err := n.articles.NotPostedArticles(ctx, time.Now().Add(-n.articleRelevance), 1)
+	channels, err := n.channels.Channels(ctx)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if len(topArticles) == 0 {
+	now := time.Now()
+
+	open := n.schedule.OpenSince(n.lastCheck, now)
+	n.lastCheck = now
+
+	if !open {
+		n.log.Info("outside posting hours, articles accumulate")
+
+		for _, channel := range channels {
+			if _, ok := n.quietSince[channel.ID]; !ok && channel.Digest.Mode == "" {
+				n.quietSince[channel.ID] = now
+			}
+		}
+
 		return nil
 	}
 
-	article := topArticles[0]
+	n.log.Info("selecting and sending articles")
+
+	// An article routed to several channels is summarized once.
+	summaries := make(map[int64]string)
 
-	summary, err := n.extractSummary(ctx, article)
+	for _, channel := range channels {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if channel.Digest.Mode != "" {
+			continue
+		}
+
+		if err := n.sendToChannel(ctx, channel, summaries, time.Now()); err != nil {
+			n.log.Error("failed to send articles to channel",
+				"channel_id", channel.ID,
+				"chat_id", channel.ChatID,
+				"err", err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// sendToChannel posts the next batch of articles to the channel. The batch
+// grows to the catch-up size when the queue is long, and sources over their
+// spacing or daily cap are skipped. After quiet hours the articles that
+// accumulated are drained at the drain batch size until the queue is short.
+func (n *Notifier) sendToChannel(
+	ctx context.Context,
+	channel model.Channel,
+	summaries map[int64]string,
+	now time.Time,
+) error {
+	quietSince, draining := n.quietSince[channel.ID]
+
+	var (
+		ranked []scoring.Scored
+		err    error
+	)
+
+	if draining {
+		ranked, err = n.ranker.RankFrom(ctx, channel.ID, quietSince, now)
+	} else {
+		ranked, err = n.ranker.Rank(ctx, channel.ID, now)
+	}
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return err
 	}
 
-	if err := n.sendArticle(article, summary); err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+	batchSize := n.cadence.postsPerTick(len(ranked))
+	if draining {
+		batchSize = n.cadence.drainPostsPerTick(len(ranked))
+
+		if len(ranked) <= batchSize {
+			delete(n.quietSince, channel.ID)
+		}
+	}
+
+	if len(ranked) == 0 {
+		return nil
+	}
+
+	history, err := n.postHistory(ctx, channel.ID, now)
+	if err != nil {
+		return err
 	}
 
-	return n.articles.MarkAsPosted(ctx, article.ID)
+	selected := n.cadence.selectArticles(ranked, batchSize, history, now)
+
+	n.log.Info("selected articles",
+		"channel_id", channel.ID,
+		"candidates", len(ranked),
+		"batch_size", batchSize,
+		"selected", len(selected),
+		"draining", draining,
+	)
+
+	for _, scored := range selected {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		article := scored.Article
+
+		n.log.Info("sending article",
+			"channel_id", channel.ID,
+			"article_id", article.ID,
+			"source_id", article.SourceID,
+			"score", scored.Breakdown.String(),
+		)
+
+		summary, ok := summaries[article.ID]
+		if !ok {
+			summary, err = n.extractSummary(ctx, article)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				// The article goes out without a summary rather than
+				// holding back the rest of the batch on every tick.
+				n.log.Warn("failed to summarize article",
+					"channel_id", channel.ID,
+					"article_id", article.ID,
+					"err", err,
+				)
+
+				summary = ""
+			}
+
+			summaries[article.ID] = summary
+		}
+
+		if err := n.sendArticle(channel.ChatID, article, summary); err != nil {
+			return err
+		}
+
+		// A sent article is marked even when ctx is cancelled meanwhile,
+		// so it is not published twice after a restart.
+		if err := n.articles.MarkAsPosted(context.WithoutCancel(ctx), channel.ID, article.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (n *Notifier) extractSummary(ctx context.Context, article model.Article) (string, error) {
-	var r io.Reader
+// postHistory loads the posts to the channel the cadence limits depend on.
+// Nothing is queried for limits that are disabled.
+func (n *Notifier) postHistory(ctx context.Context, channelID int64, now time.Time) (postHistory, error) {
+	history := postHistory{
+		lastPosted:  make(map[int64]time.Time),
+		postedToday: make(map[int64]int),
+	}
 
-	if article.Summary != "" {
-		r = strings.NewReader(article.Summary)
-	} else {
-		resp, err := http.Get(article.Link)
+	if n.cadence.SourceSpacing > 0 {
+		lastPosted, err := n.articles.LastPostedBySource(ctx, channelID, now.Add(-n.cadence.SourceSpacing))
 		if err != nil {
-			return "", err
+			return postHistory{}, err
+		}
+
+		history.lastPosted = lastPosted
+	}
+
+	if n.cadence.SourceDailyCap > 0 {
+		postedToday, err := n.articles.PostedCountBySource(ctx, channelID, now.Add(-24*time.Hour))
+		if err != nil {
+			return postHistory{}, err
 		}
-		defer resp.Body.Close()
 
-		r = resp.Body
+		history.postedToday = postedToday
 	}
 
-	doc, err := readability.FromReader(r, nil)
+	return history, nil
+}
+
+// extractSummary summarizes the feed summary of the article, or its full
+// text when the feed has none. The text stored at fetch time is used when
+// available, otherwise the page is downloaded now. Articles without any
+// text get no summary.
+func (n *Notifier) extractSummary(ctx context.Context, article model.Article) (string, error) {
+	text, err := n.articleText(ctx, article)
 	if err != nil {
 		return "", err
 	}
 
-	summary, err := n.summarizer.Summarize(ctx, cleanExtraEmptyLines(doc.TextContent))
+	if strings.TrimSpace(text) == "" {
+		return "", nil
+	}
+
+	summary, err := n.summarizer.Summarize(ctx, text)
 	if err != nil {
 		return "", err
 	}
@@ -134,14 +336,45 @@ func (n *Notifier) extractSummary(ctx context.Context, article model.Article) (s
 	return "\n\n" + summary, nil
 }
 
-func cleanExtraEmptyLines(text string) string {
-	return regexp.MustCompile(`\n{3,}`).ReplaceAllString(text, "\n")
+func (n *Notifier) articleText(ctx context.Context, article model.Article) (string, error) {
+	if article.Summary != "" {
+		content, err := extract.FromReader(strings.NewReader(article.Summary), nil)
+		if err == nil {
+			return content.Text, nil
+		}
+
+		if !errors.Is(err, extract.ErrNoContent) {
+			return "", err
+		}
+	}
+
+	if article.Text != "" {
+		return article.Text, nil
+	}
+
+	// The page could not be extracted at fetch time, downloading it again
+	// for every post would fail the same way.
+	if article.ExtractionError != "" {
+		return "", nil
+	}
+
+	src, err := n.sources.SourceById(ctx, article.SourceID)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := n.extractor.Extract(ctx, *src, article.Link)
+	if err != nil {
+		return "", err
+	}
+
+	return content.Text, nil
 }
 
-func (n *Notifier) sendArticle(article model.Article, summary string) error {
+func (n *Notifier) sendArticle(chatID int64, article model.Article, summary string) error {
 	const msgFormat = "*%s*%s\n\n%s"
 
-	msg := tgbotapi.NewMessage(n.channelID, fmt.Sprintf(
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(
 		msgFormat,
 		markup.EscapeForMarkdown(article.Title),
 		markup.EscapeForMarkdown(summary),
@@ -0,0 +1,164 @@
+package opml
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"news-feed-bot/internal/model"
+	"time"
+)
+
+// Document is an OPML 2.0 subscription list (http://opml.org/spec2.opml).
+type Document struct {
+	XMLName xml.Name  `xml:"opml"`
+	Version string    `xml:"version,attr"`
+	Head    Head      `xml:"head"`
+	Body    []Outline `xml:"body>outline"`
+}
+
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type Outline struct {
+	Text    string `xml:"text,attr"`
+	Title   string `xml:"title,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	XMLURL  string `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL string `xml:"htmlUrl,attr,omitempty"`
+	// Selectors and HTTPSettings are extensions of this bot holding the
+	// JSON encoded settings of a source, so exports can be imported back.
+	Selectors    string    `xml:"selectors,attr,omitempty"`
+	HTTPSettings string    `xml:"httpSettings,attr,omitempty"`
+	Outlines     []Outline `xml:"outline"`
+}
+
+// Feed is a subscription read from or written to an OPML document.
+type Feed struct {
+	Title     string
+	Type      string
+	URL       string
+	Selectors *model.ScrapeSelectors
+	HTTP      *model.HTTPSettings
+	// Err is set when the outline carries settings that cannot be decoded.
+	// Parse still returns such feeds so one bad entry does not fail the
+	// whole document.
+	Err error
+}
+
+// Parse reads an OPML document and returns its subscriptions. Outlines
+// nested in folders are flattened; outlines without xmlUrl are skipped.
+// Outlines with malformed settings are returned with Err set.
+func Parse(r io.Reader) ([]Feed, error) {
+	const op = "opml.Parse"
+
+	var doc Document
+
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var feeds []Feed
+
+	var walk func([]Outline)
+	walk = func(outlines []Outline) {
+		for _, o := range outlines {
+			if o.XMLURL != "" {
+				feeds = append(feeds, outlineFeed(o))
+			}
+
+			walk(o.Outlines)
+		}
+	}
+
+	walk(doc.Body)
+
+	return feeds, nil
+}
+
+func outlineFeed(o Outline) Feed {
+	feed := Feed{Title: o.Title, Type: o.Type, URL: o.XMLURL}
+	if feed.Title == "" {
+		feed.Title = o.Text
+	}
+
+	if o.Selectors != "" {
+		if err := json.Unmarshal([]byte(o.Selectors), &feed.Selectors); err != nil {
+			feed.Selectors = nil
+			feed.Err = fmt.Errorf("invalid selectors: %w", err)
+
+			return feed
+		}
+	}
+
+	if o.HTTPSettings != "" {
+		if err := json.Unmarshal([]byte(o.HTTPSettings), &feed.HTTP); err != nil {
+			feed.HTTP = nil
+			feed.Err = fmt.Errorf("invalid http settings: %w", err)
+
+			return feed
+		}
+	}
+
+	return feed
+}
+
+// Write encodes feeds as an OPML 2.0 document.
+func Write(w io.Writer, title string, feeds []Feed) error {
+	const op = "opml.Write"
+
+	doc := Document{
+		Version: "2.0",
+		Head: Head{
+			Title:       title,
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	for _, feed := range feeds {
+		outline := Outline{
+			Text:   feed.Title,
+			Title:  feed.Title,
+			Type:   feed.Type,
+			XMLURL: feed.URL,
+		}
+
+		if feed.Selectors != nil {
+			raw, err := json.Marshal(feed.Selectors)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			outline.Selectors = string(raw)
+		}
+
+		if feed.HTTP != nil {
+			raw, err := json.Marshal(feed.HTTP)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			outline.HTTPSettings = string(raw)
+		}
+
+		doc.Body = append(doc.Body, outline)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
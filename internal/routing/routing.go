@@ -0,0 +1,129 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"news-feed-bot/internal/filter"
+	"news-feed-bot/internal/model"
+	"strings"
+)
+
+var ErrInvalidRoute = errors.New("invalid route")
+
+// Route is a channel route ready to be matched against articles.
+type Route struct {
+	model.ChannelRoute
+	rule *filter.Rule
+}
+
+// Compile validates r. A route names a source, a source group or a filter
+// pattern, which is matched like an include filter rule.
+func Compile(r model.ChannelRoute) (*Route, error) {
+	const op = "routing.Compile"
+
+	r.Group = strings.TrimSpace(r.Group)
+
+	targets := 0
+	for _, set := range []bool{r.SourceID != 0, r.Group != "", r.Pattern != ""} {
+		if set {
+			targets++
+		}
+	}
+
+	if targets != 1 {
+		return nil, fmt.Errorf("%s: %w: set exactly one of source, group or pattern", op, ErrInvalidRoute)
+	}
+
+	route := &Route{ChannelRoute: r}
+
+	if r.Pattern != "" {
+		rule, err := filter.Compile(model.FilterRule{
+			Action:  filter.ActionInclude,
+			Field:   r.Field,
+			Match:   r.Match,
+			Pattern: r.Pattern,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", op, ErrInvalidRoute, err)
+		}
+
+		route.rule = rule
+	}
+
+	return route, nil
+}
+
+func (r *Route) Matches(article model.Article, src model.Source) bool {
+	switch {
+	case r.SourceID != 0:
+		return article.SourceID == r.SourceID
+	case r.Group != "":
+		return strings.EqualFold(src.Group, r.Group)
+	}
+
+	return r.rule.Matches(filter.ArticleItem(article))
+}
+
+func Describe(r model.ChannelRoute) string {
+	var b strings.Builder
+
+	if r.ID != 0 {
+		fmt.Fprintf(&b, "#%d ", r.ID)
+	}
+
+	switch {
+	case r.SourceID != 0:
+		fmt.Fprintf(&b, "source %d", r.SourceID)
+	case r.Group != "":
+		fmt.Fprintf(&b, "group %q", r.Group)
+	default:
+		fmt.Fprintf(&b, "%s %s %q", r.Field, r.Match, r.Pattern)
+	}
+
+	return b.String()
+}
+
+// Router decides which channels an article is published to.
+type Router struct {
+	routes map[int64][]*Route
+}
+
+func New() *Router {
+	return &Router{routes: make(map[int64][]*Route)}
+}
+
+// Add registers a route. A channel with an invalid route still counts as
+// routed, so it does not fall back to receiving every article.
+func (r *Router) Add(route model.ChannelRoute) error {
+	const op = "routing.Router.Add"
+
+	compiled, err := Compile(route)
+	if err != nil {
+		if _, ok := r.routes[route.ChannelID]; !ok {
+			r.routes[route.ChannelID] = nil
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.routes[route.ChannelID] = append(r.routes[route.ChannelID], compiled)
+
+	return nil
+}
+
+// Routed reports whether the article goes to the channel. A channel without
+// routes receives every article.
+func (r *Router) Routed(channelID int64, article model.Article, src model.Source) bool {
+	routes, ok := r.routes[channelID]
+	if !ok {
+		return true
+	}
+
+	for _, route := range routes {
+		if route.Matches(article, src) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"* * * * 5-2",
+		"*/0 * * * *",
+		"* * * foo *",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseCron(expr); !errors.Is(err, ErrInvalidSchedule) {
+				t.Errorf("ParseCron(%q) error = %v, want ErrInvalidSchedule", expr, err)
+			}
+		})
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	// 2024-06-02 is a Sunday, 2024-06-03 a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.June, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{"every minute", "* * * * *", at(3, 13, 37), true},
+		{"exact minute", "30 9 * * *", at(3, 9, 30), true},
+		{"other minute", "30 9 * * *", at(3, 9, 31), false},
+		{"list", "0 9,12,18 * * *", at(3, 12, 0), true},
+		{"list miss", "0 9,12,18 * * *", at(3, 13, 0), false},
+		{"range", "0 9-17 * * *", at(3, 17, 0), true},
+		{"range miss", "0 9-17 * * *", at(3, 18, 0), false},
+		{"step", "*/15 * * * *", at(3, 10, 45), true},
+		{"step miss", "*/15 * * * *", at(3, 10, 50), false},
+		{"step from value", "5/20 * * * *", at(3, 10, 45), true},
+		{"range with step", "0 8-18/2 * * *", at(3, 14, 0), true},
+		{"range with step miss", "0 8-18/2 * * *", at(3, 15, 0), false},
+		{"month name", "0 9 * jun *", at(3, 9, 0), true},
+		{"month name miss", "0 9 * jul *", at(3, 9, 0), false},
+		{"weekday name", "0 9 * * mon", at(3, 9, 0), true},
+		{"weekday name miss", "0 9 * * mon", at(2, 9, 0), false},
+		{"sunday as 0", "0 9 * * 0", at(2, 9, 0), true},
+		{"sunday as 7", "0 9 * * 7", at(2, 9, 0), true},
+		{"range up to 7", "0 9 * * 5-7", at(2, 9, 0), true},
+		{"range up to 7 miss", "0 9 * * 5-7", at(3, 9, 0), false},
+		{"day of month", "0 9 3 * *", at(3, 9, 0), true},
+		{"day of month miss", "0 9 4 * *", at(3, 9, 0), false},
+		{"either day field", "0 9 4 * mon", at(3, 9, 0), true},
+		{"either day field by dom", "0 9 2 * mon", at(2, 9, 0), true},
+		{"neither day field", "0 9 4 * tue", at(3, 9, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+
+			if got := c.Matches(tt.t); got != tt.want {
+				t.Errorf("%q.Matches(%s) = %v, want %v", tt.expr, tt.t.Format(time.RFC1123), got, tt.want)
+			}
+		})
+	}
+}
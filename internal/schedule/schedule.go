@@ -0,0 +1,113 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	// Time zones load without a zoneinfo database on the host.
+	_ "time/tzdata"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// maxCronLookback bounds how far back OpenSince looks for cron occurrences.
+const maxCronLookback = 24 * time.Hour
+
+// Schedule tells when posting is allowed: during any of its windows or in
+// any minute matching one of its cron expressions, in its time zone. A
+// schedule without windows and cron expressions is always open.
+type Schedule struct {
+	loc     *time.Location
+	windows []*Window
+	crons   []*Cron
+}
+
+// New builds a schedule in the IANA time zone, e.g. "Europe/Berlin".
+// An empty time zone means UTC.
+func New(timeZone string, windows []string, crons []string) (*Schedule, error) {
+	const op = "schedule.New"
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: time zone %q: %w", op, ErrInvalidSchedule, timeZone, err)
+	}
+
+	s := &Schedule{loc: loc}
+
+	for _, spec := range windows {
+		w, err := ParseWindow(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.windows = append(s.windows, w)
+	}
+
+	for _, expr := range crons {
+		c, err := ParseCron(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.crons = append(s.crons, c)
+	}
+
+	return s, nil
+}
+
+func (s *Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// Open reports whether posting is allowed at t.
+func (s *Schedule) Open(t time.Time) bool {
+	if len(s.windows) == 0 && len(s.crons) == 0 {
+		return true
+	}
+
+	t = t.In(s.loc)
+
+	for _, w := range s.windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+
+	for _, c := range s.crons {
+		if c.Matches(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OpenSince reports whether posting is allowed at now or a cron expression
+// selected a minute after the one of since. Callers checking on a ticker
+// pass the time of their previous check, so the minutes selected between
+// two ticks are not missed. A zero since only checks now.
+func (s *Schedule) OpenSince(since, now time.Time) bool {
+	if s.Open(now) {
+		return true
+	}
+
+	if since.IsZero() || len(s.crons) == 0 {
+		return false
+	}
+
+	if earliest := now.Add(-maxCronLookback); since.Before(earliest) {
+		since = earliest
+	}
+
+	for t := since.Truncate(time.Minute).Add(time.Minute); !t.After(now); t = t.Add(time.Minute) {
+		local := t.In(s.loc)
+
+		for _, c := range s.crons {
+			if c.Matches(local) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
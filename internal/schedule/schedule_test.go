@@ -0,0 +1,53 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleOpenSince(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2024-06-03 is a Monday; Berlin is at UTC+2.
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.June, 3, hour, minute, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name    string
+		windows []string
+		crons   []string
+		since   time.Time
+		now     time.Time
+		want    bool
+	}{
+		{"always open", nil, nil, time.Time{}, at(3, 0), true},
+		{"in window", []string{"08:00-10:00"}, nil, time.Time{}, at(9, 0), true},
+		{"outside window", []string{"08:00-10:00"}, nil, at(10, 0), at(10, 10), false},
+		{"window in schedule time zone", []string{"08:00-10:00"}, nil, time.Time{}, at(9, 0).UTC(), true},
+		{"cron minute is now", nil, []string{"0 9 * * *"}, time.Time{}, at(9, 0), true},
+		{"cron minute between ticks", nil, []string{"0 9 * * *"}, at(8, 55), at(9, 5), true},
+		{"cron minute right after since", nil, []string{"0 9 * * *"}, at(8, 59), at(9, 9), true},
+		{"cron minute of since", nil, []string{"0 9 * * *"}, at(9, 0), at(9, 10), false},
+		{"cron minute after now", nil, []string{"0 9 * * *"}, at(8, 40), at(8, 50), false},
+		{"cron without since", nil, []string{"0 9 * * *"}, time.Time{}, at(9, 5), false},
+		{"cron in schedule time zone", nil, []string{"0 9 * * *"}, at(8, 55).UTC(), at(9, 5).UTC(), true},
+		{"cron looks back a day at most", nil, []string{"0 9 * * fri"}, at(8, 0).AddDate(0, 0, -3), at(8, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New("Europe/Berlin", tt.windows, tt.crons)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			if got := s.OpenSince(tt.since, tt.now); got != tt.want {
+				t.Errorf("OpenSince(%s, %s) = %v, want %v", tt.since, tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a weekday and time of day range such as "mon-fri 08:00-22:00"
+// or "sat,sun 10:00-20:00". Without days it applies to every day. A range
+// ending before it starts runs past midnight into the next day.
+type Window struct {
+	days       [7]bool
+	start, end int
+}
+
+func ParseWindow(spec string) (*Window, error) {
+	const op = "schedule.ParseWindow"
+
+	fields := strings.Fields(spec)
+
+	var w Window
+
+	switch len(fields) {
+	case 1:
+		for i := range w.days {
+			w.days[i] = true
+		}
+	case 2:
+		if err := w.parseDays(fields[0]); err != nil {
+			return nil, fmt.Errorf("%s: %w: %q: %w", op, ErrInvalidSchedule, spec, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: %w: %q is not [days] HH:MM-HH:MM", op, ErrInvalidSchedule, spec)
+	}
+
+	startPart, endPart, ok := strings.Cut(fields[len(fields)-1], "-")
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: %q has no time range", op, ErrInvalidSchedule, spec)
+	}
+
+	var err error
+
+	if w.start, err = parseMinuteOfDay(startPart); err != nil {
+		return nil, fmt.Errorf("%s: %w: %q: %w", op, ErrInvalidSchedule, spec, err)
+	}
+
+	if w.end, err = parseMinuteOfDay(endPart); err != nil {
+		return nil, fmt.Errorf("%s: %w: %q: %w", op, ErrInvalidSchedule, spec, err)
+	}
+
+	if w.start == w.end {
+		return nil, fmt.Errorf("%s: %w: %q is empty", op, ErrInvalidSchedule, spec)
+	}
+
+	return &w, nil
+}
+
+// Contains reports whether t falls in the window. t is taken in its own
+// location.
+func (w *Window) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+
+	if w.start < w.end {
+		return w.days[day] && minute >= w.start && minute < w.end
+	}
+
+	previous := (day + 6) % 7
+
+	return (w.days[day] && minute >= w.start) || (w.days[previous] && minute < w.end)
+}
+
+func (w *Window) parseDays(spec string) error {
+	for _, part := range strings.Split(spec, ",") {
+		firstPart, lastPart, isRange := strings.Cut(part, "-")
+
+		first, err := dowField.value(firstPart)
+		if err != nil {
+			return err
+		}
+
+		last := first
+		if isRange {
+			if last, err = dowField.value(lastPart); err != nil {
+				return err
+			}
+		}
+
+		// Ranges wrap around the week, so "fri-mon" works.
+		for day := first; ; day = (day + 1) % 7 {
+			w.days[day%7] = true
+
+			if day%7 == last%7 {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseMinuteOfDay parses "HH:MM", allowing "24:00" as the end of the day.
+func parseMinuteOfDay(s string) (int, error) {
+	if s == "24:00" {
+		return 24 * 60, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("bad time %q", s)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
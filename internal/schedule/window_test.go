@@ -0,0 +1,74 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseWindowInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"08:00",
+		"mon-fri",
+		"mon fri 08:00-10:00",
+		"08:00-08:00",
+		"25:00-26:00",
+		"8am-10am",
+		"funday 08:00-10:00",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := ParseWindow(spec); !errors.Is(err, ErrInvalidSchedule) {
+				t.Errorf("ParseWindow(%q) error = %v, want ErrInvalidSchedule", spec, err)
+			}
+		})
+	}
+}
+
+func TestWindowContains(t *testing.T) {
+	// 2024-06-07 is a Friday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.June, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		t    time.Time
+		want bool
+	}{
+		{"every day", "08:00-22:00", at(8, 12, 0), true},
+		{"start is inside", "08:00-22:00", at(8, 8, 0), true},
+		{"end is outside", "08:00-22:00", at(8, 22, 0), false},
+		{"before start", "08:00-22:00", at(8, 7, 59), false},
+		{"weekday range", "mon-fri 08:00-22:00", at(7, 12, 0), true},
+		{"weekday range miss", "mon-fri 08:00-22:00", at(8, 12, 0), false},
+		{"weekday list", "sat,sun 10:00-20:00", at(9, 10, 0), true},
+		{"weekday list miss", "sat,sun 10:00-20:00", at(7, 10, 0), false},
+		{"range across the week", "fri-mon 10:00-20:00", at(10, 10, 0), true},
+		{"range across the week miss", "fri-mon 10:00-20:00", at(11, 10, 0), false},
+		{"sunday as 7", "7 10:00-20:00", at(9, 10, 0), true},
+		{"until midnight", "20:00-24:00", at(8, 23, 59), true},
+		{"overnight evening", "22:00-06:00", at(8, 23, 0), true},
+		{"overnight morning", "22:00-06:00", at(8, 5, 59), true},
+		{"overnight end", "22:00-06:00", at(8, 6, 0), false},
+		{"overnight day", "22:00-06:00", at(8, 12, 0), false},
+		{"overnight from listed day", "fri 22:00-02:00", at(7, 23, 0), true},
+		{"overnight into next day", "fri 22:00-02:00", at(8, 1, 0), true},
+		{"overnight from other day", "fri 22:00-02:00", at(7, 1, 0), false},
+		{"overnight evening of next day", "fri 22:00-02:00", at(8, 23, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseWindow(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseWindow(%q): %v", tt.spec, err)
+			}
+
+			if got := w.Contains(tt.t); got != tt.want {
+				t.Errorf("%q.Contains(%s) = %v, want %v", tt.spec, tt.t.Format(time.RFC1123), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package scoring
+
+import (
+	"context"
+	"fmt"
+	"news-feed-bot/internal/model"
+	"news-feed-bot/internal/routing"
+	"sort"
+	"time"
+)
+
+// maxCandidatePages bounds how many pages of unposted articles are read
+// looking for articles routed to a channel.
+const maxCandidatePages = 10
+
+type ArticleProvider interface {
+	// NotPostedArticles returns the articles not posted to the channel,
+	// newest first.
+	NotPostedArticles(ctx context.Context, channelID int64, since time.Time, limit uint64, offset uint64) ([]model.Article, error)
+	// LastPostedBySource maps source ids to their latest post to the channel
+	// since the given time.
+	LastPostedBySource(ctx context.Context, channelID int64, since time.Time) (map[int64]time.Time, error)
+}
+
+type SourceProvider interface {
+	Sources(ctx context.Context) ([]model.Source, error)
+}
+
+type RouteProvider interface {
+	Routes(ctx context.Context) ([]model.ChannelRoute, error)
+}
+
+// Scored is a candidate article with its score.
+type Scored struct {
+	Article   model.Article
+	Source    model.Source
+	Breakdown Breakdown
+}
+
+// Ranker scores the articles not yet posted to a channel and orders them
+// best first.
+type Ranker struct {
+	articles   ArticleProvider
+	sources    SourceProvider
+	routes     RouteProvider
+	scorer     *Scorer
+	candidates uint64
+	relevance  time.Duration
+}
+
+// NewRanker builds a ranker considering up to candidates of the newest
+// unposted articles routed to a channel and published within relevance.
+func NewRanker(
+	articles ArticleProvider,
+	sources SourceProvider,
+	routes RouteProvider,
+	scorer *Scorer,
+	candidates int,
+	relevance time.Duration,
+) *Ranker {
+	return &Ranker{
+		articles:   articles,
+		sources:    sources,
+		routes:     routes,
+		scorer:     scorer,
+		candidates: uint64(max(candidates, 1)),
+		relevance:  relevance,
+	}
+}
+
+// Rank returns the candidates routed to the channel. Routes that do not
+// compile are skipped; they are validated when they are added.
+func (r *Ranker) Rank(ctx context.Context, channelID int64, now time.Time) ([]Scored, error) {
+	const op = "scoring.Ranker.Rank"
+
+	scored, err := r.RankWindow(ctx, channelID, now.Add(-r.relevance), int(r.candidates), now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scored, nil
+}
+
+// RankFrom is Rank with the relevance window ending at from instead of now,
+// so articles that accumulated since then are still candidates.
+func (r *Ranker) RankFrom(ctx context.Context, channelID int64, from time.Time, now time.Time) ([]Scored, error) {
+	const op = "scoring.Ranker.RankFrom"
+
+	scored, err := r.RankWindow(ctx, channelID, from.Add(-r.relevance), int(r.candidates), now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scored, nil
+}
+
+// RankWindow is Rank over up to candidates of the newest articles published
+// since the given time, for callers looking further back than the relevance
+// window. Articles are read page by page until enough of them are routed to
+// the channel, so a channel routed to quiet sources is not starved by busy
+// ones.
+func (r *Ranker) RankWindow(
+	ctx context.Context,
+	channelID int64,
+	since time.Time,
+	candidates int,
+	now time.Time,
+) ([]Scored, error) {
+	const op = "scoring.Ranker.RankWindow"
+
+	limit := uint64(max(candidates, 1))
+
+	articles, err := r.articles.NotPostedArticles(ctx, channelID, since, limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(articles) == 0 {
+		return nil, nil
+	}
+
+	sources, err := r.sources.Sources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byID := make(map[int64]model.Source, len(sources))
+	for _, src := range sources {
+		byID[src.ID] = src
+	}
+
+	routes, err := r.routes.Routes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	router := routing.New()
+	for _, route := range routes {
+		_ = router.Add(route)
+	}
+
+	lastPosted, err := r.articles.LastPostedBySource(ctx, channelID, now.Add(-r.scorer.model.RecentSourceWindow))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	scored := make([]Scored, 0, len(articles))
+
+	for page := uint64(1); ; page++ {
+		for _, article := range articles {
+			src, ok := byID[article.SourceID]
+			if !ok {
+				// The source was deleted after the article was stored.
+				src = model.Source{ID: article.SourceID, Priority: 1}
+			}
+
+			if !router.Routed(channelID, article, src) {
+				continue
+			}
+
+			scored = append(scored, Scored{
+				Article:   article,
+				Source:    src,
+				Breakdown: r.scorer.Score(article, src, lastPosted[article.SourceID], now),
+			})
+		}
+
+		if uint64(len(scored)) >= limit || uint64(len(articles)) < limit || page >= maxCandidatePages {
+			break
+		}
+
+		articles, err = r.articles.NotPostedArticles(ctx, channelID, since, limit, page*limit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	scored = scored[:min(uint64(len(scored)), limit)]
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Breakdown.Total > scored[j].Breakdown.Total
+	})
+
+	return scored, nil
+}
@@ -0,0 +1,126 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"news-feed-bot/internal/filter"
+	"news-feed-bot/internal/model"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Model turns an article into a score. The total is
+//
+//	priority × (freshness + keywords) − recent source penalty
+//
+// where freshness halves every FreshnessHalfLife and the penalty fades out
+// linearly over RecentSourceWindow after the source was last posted.
+type Model struct {
+	FreshnessHalfLife time.Duration
+	// Keywords maps whole words or phrases to the score added when the
+	// title or summary contains them. Negative values are penalties.
+	Keywords            map[string]float64
+	RecentSourceWindow  time.Duration
+	RecentSourcePenalty float64
+}
+
+// Breakdown is the score of an article along with what it is made of.
+type Breakdown struct {
+	Priority         float64
+	Freshness        float64
+	Keywords         float64
+	KeywordMatches   []string
+	RecentPenalty    float64
+	SourceLastPosted time.Time
+	Total            float64
+}
+
+func (b Breakdown) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%.2f = %.2f × (freshness %.2f + keywords %+.2f) − recent source %.2f",
+		b.Total, b.Priority, b.Freshness, b.Keywords, b.RecentPenalty)
+
+	if len(b.KeywordMatches) > 0 {
+		fmt.Fprintf(&sb, " [%s]", strings.Join(b.KeywordMatches, ", "))
+	}
+
+	return sb.String()
+}
+
+type keywordRule struct {
+	keyword string
+	weight  float64
+	rule    *filter.Rule
+}
+
+// Scorer applies a Model. Keywords are compiled once when it is built.
+type Scorer struct {
+	model    Model
+	keywords []keywordRule
+}
+
+func NewScorer(m Model) (*Scorer, error) {
+	const op = "scoring.NewScorer"
+
+	s := &Scorer{model: m}
+
+	for keyword, weight := range m.Keywords {
+		rule, err := filter.Compile(model.FilterRule{
+			Action:  filter.ActionInclude,
+			Field:   filter.FieldAny,
+			Match:   filter.MatchWord,
+			Pattern: keyword,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: keyword %q: %w", op, keyword, err)
+		}
+
+		s.keywords = append(s.keywords, keywordRule{keyword: keyword, weight: weight, rule: rule})
+	}
+
+	// Map order is random, keep breakdowns stable.
+	sort.Slice(s.keywords, func(i, j int) bool { return s.keywords[i].keyword < s.keywords[j].keyword })
+
+	return s, nil
+}
+
+// Score rates article from src. lastPosted is when src was last posted to
+// the channel, zero if never.
+func (s *Scorer) Score(article model.Article, src model.Source, lastPosted time.Time, now time.Time) Breakdown {
+	b := Breakdown{
+		Priority:         src.Priority,
+		Freshness:        s.freshness(article.PublishedAt, now),
+		SourceLastPosted: lastPosted,
+	}
+
+	item := model.Item{Title: article.Title, Summary: article.Summary}
+
+	for _, k := range s.keywords {
+		if k.rule.Matches(item) {
+			b.Keywords += k.weight
+			b.KeywordMatches = append(b.KeywordMatches, fmt.Sprintf("%s %+g", k.keyword, k.weight))
+		}
+	}
+
+	if !lastPosted.IsZero() && s.model.RecentSourceWindow > 0 {
+		if since := now.Sub(lastPosted); since < s.model.RecentSourceWindow {
+			b.RecentPenalty = s.model.RecentSourcePenalty * (1 - float64(since)/float64(s.model.RecentSourceWindow))
+		}
+	}
+
+	b.Total = b.Priority*(b.Freshness+b.Keywords) - b.RecentPenalty
+
+	return b
+}
+
+func (s *Scorer) freshness(publishedAt time.Time, now time.Time) float64 {
+	if s.model.FreshnessHalfLife <= 0 {
+		return 1
+	}
+
+	age := max(now.Sub(publishedAt), 0)
+
+	return math.Exp2(-float64(age) / float64(s.model.FreshnessHalfLife))
+}
@@ -0,0 +1,65 @@
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// Hash returns the 64-bit SimHash of features. Similar feature sets give
+// hashes that differ in few bits.
+func Hash(features []string) uint64 {
+	var weights [64]int
+
+	for _, feature := range features {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(feature))
+		sum := h.Sum64()
+
+		for i := 0; i < 64; i++ {
+			if sum&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var hash uint64
+
+	for i, w := range weights {
+		if w > 0 {
+			hash |= 1 << i
+		}
+	}
+
+	return hash
+}
+
+// Distance returns the number of bits in which a and b differ.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Words splits text into lowercased words, dropping punctuation.
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Grams returns the overlapping n-character slices of the words joined by
+// single spaces. Character grams keep the hash stable when a short text
+// loses or gains a word.
+func Grams(words []string, n int) []string {
+	text := []rune(" " + strings.Join(words, " ") + " ")
+
+	var grams []string
+
+	for i := 0; i+n <= len(text); i++ {
+		grams = append(grams, string(text[i:i+n]))
+	}
+
+	return grams
+}
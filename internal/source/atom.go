@@ -0,0 +1,199 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"golang.org/x/net/html/charset"
+	"net/http"
+	"news-feed-bot/internal/model"
+	"strings"
+	"time"
+)
+
+// AtomSource reads Atom 1.0 (RFC 4287) feeds without going through
+// the generic RSS parser.
+type AtomSource struct {
+	URL        string
+	SourceID   int64
+	SourceName string
+
+	httpCache
+	feedTitle
+}
+
+func NewAtomSource(m model.Source, client *http.Client) *AtomSource {
+	return &AtomSource{
+		URL:        m.FeedURL,
+		SourceID:   m.ID,
+		SourceName: m.Name,
+		httpCache:  newHTTPCache(m, client),
+	}
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   atomText    `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title      atomText       `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    atomText       `xml:"summary"`
+	Content    atomText       `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+	Authors    []atomPerson   `xml:"author"`
+}
+
+// atomText is an Atom text construct. Plain text and escaped HTML come
+// through as character data, XHTML as nested markup.
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+func (t atomText) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+
+	return strings.TrimSpace(t.Text)
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+func (a *AtomSource) Fetch(ctx context.Context) ([]model.Item, error) {
+	const op = "source.atom.Fetch"
+
+	data, err := a.get(ctx, a.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	feed, err := parseAtom(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrParse, err)
+	}
+
+	a.title = feed.Title.String()
+
+	var items []model.Item
+
+	for _, entry := range feed.Entries {
+		items = append(items, model.Item{
+			Title:      entry.Title.String(),
+			Categories: entry.categories(),
+			Link:       entry.link(),
+			Date:       entry.date(),
+			Summary:    entry.summary(),
+			Author:     entry.author(),
+			SourceName: a.SourceName,
+		})
+	}
+
+	return items, nil
+}
+
+func parseAtom(data []byte) (*atomFeed, error) {
+	var feed atomFeed
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	if err := decoder.Decode(&feed); err != nil {
+		return nil, fmt.Errorf("parse atom: %w", err)
+	}
+
+	return &feed, nil
+}
+
+// link prefers the rel="alternate" link, which per RFC 4287 is also
+// the meaning of a link without rel.
+func (e atomEntry) link() string {
+	var fallback string
+
+	for _, l := range e.Links {
+		switch l.Rel {
+		case "", "alternate":
+			return l.Href
+		default:
+			if fallback == "" {
+				fallback = l.Href
+			}
+		}
+	}
+
+	return fallback
+}
+
+func (e atomEntry) date() time.Time {
+	for _, raw := range []string{e.Published, e.Updated} {
+		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(raw)); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (e atomEntry) summary() string {
+	if summary := e.Summary.String(); summary != "" {
+		return summary
+	}
+
+	return e.Content.String()
+}
+
+func (e atomEntry) categories() []string {
+	var categories []string
+
+	for _, c := range e.Categories {
+		if c.Label != "" {
+			categories = append(categories, c.Label)
+			continue
+		}
+
+		categories = append(categories, c.Term)
+	}
+
+	return categories
+}
+
+func (e atomEntry) author() string {
+	var names []string
+
+	for _, p := range e.Authors {
+		if name := strings.TrimSpace(p.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func (a *AtomSource) ID() int64 {
+	return a.SourceID
+}
+
+func (a *AtomSource) Name() string {
+	return a.SourceName
+}
@@ -0,0 +1,159 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"news-feed-bot/internal/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// serveFeed serves body at the URL it returns for the duration of the test.
+func serveFeed(t *testing.T, body string) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL
+}
+
+func TestAtomSourceFetch(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       string
+		wantTitle string
+		want      []model.Item
+	}{
+		{
+			name: "entries",
+			doc: `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example Blog</title>
+  <entry>
+    <title> First post </title>
+    <link rel="edit" href="https://example.com/edit/1"/>
+    <link href="https://example.com/posts/1"/>
+    <published>2024-06-03T09:30:00+02:00</published>
+    <updated>2024-06-04T10:00:00Z</updated>
+    <summary type="html">&lt;p&gt;Short summary&lt;/p&gt;</summary>
+    <content type="html">&lt;p&gt;Full content&lt;/p&gt;</content>
+    <category term="go" label="Go"/>
+    <category term="web"/>
+    <author><name>Jane Doe</name></author>
+    <author><name>John Roe</name></author>
+  </entry>
+  <entry>
+    <title>Second post</title>
+    <link rel="alternate" type="text/html" href="https://example.com/posts/2"/>
+    <updated>2024-06-05T08:00:00Z</updated>
+    <content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">Only <b>content</b></div></content>
+  </entry>
+  <entry>
+    <title>Undated</title>
+    <link rel="related" href="https://example.com/related"/>
+    <published>yesterday</published>
+  </entry>
+</feed>`,
+			wantTitle: "Example Blog",
+			want: []model.Item{
+				{
+					Title:      "First post",
+					Categories: []string{"Go", "web"},
+					Link:       "https://example.com/posts/1",
+					Date:       time.Date(2024, time.June, 3, 7, 30, 0, 0, time.UTC),
+					Summary:    "<p>Short summary</p>",
+					Author:     "Jane Doe, John Roe",
+					SourceName: "Example",
+				},
+				{
+					Title:      "Second post",
+					Link:       "https://example.com/posts/2",
+					Date:       time.Date(2024, time.June, 5, 8, 0, 0, 0, time.UTC),
+					Summary:    `<div xmlns="http://www.w3.org/1999/xhtml">Only <b>content</b></div>`,
+					SourceName: "Example",
+				},
+				{
+					Title:      "Undated",
+					Link:       "https://example.com/related",
+					SourceName: "Example",
+				},
+			},
+		},
+		{
+			name: "latin-1 encoding",
+			doc: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+				"<feed xmlns=\"http://www.w3.org/2005/Atom\"><title>Caf\xe9</title>" +
+				"<entry><title>Cr\xe8me br\xfbl\xe9e</title><link href=\"https://example.com/c\"/>" +
+				"<author><name>Fran\xe7ois</name></author></entry></feed>",
+			wantTitle: "Café",
+			want: []model.Item{
+				{
+					Title:      "Crème brûlée",
+					Link:       "https://example.com/c",
+					Author:     "François",
+					SourceName: "Example",
+				},
+			},
+		},
+		{
+			name: "windows-1251 encoding",
+			doc: "<?xml version=\"1.0\" encoding=\"windows-1251\"?>\n" +
+				"<feed xmlns=\"http://www.w3.org/2005/Atom\"><title>\xcd\xee\xe2\xee\xf1\xf2\xe8</title>" +
+				"<entry><title>\xcf\xf0\xe8\xe2\xe5\xf2</title><link href=\"https://example.com/r\"/></entry></feed>",
+			wantTitle: "Новости",
+			want: []model.Item{
+				{
+					Title:      "Привет",
+					Link:       "https://example.com/r",
+					SourceName: "Example",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewAtomSource(model.Source{FeedURL: serveFeed(t, tt.doc), Name: "Example"}, nil)
+
+			items, err := src.Fetch(context.Background())
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+
+			for i := range items {
+				if !items[i].Date.IsZero() {
+					items[i].Date = items[i].Date.UTC()
+				}
+			}
+
+			if !reflect.DeepEqual(items, tt.want) {
+				t.Errorf("Fetch items =\n%+v\nwant\n%+v", items, tt.want)
+			}
+
+			if got := src.FeedTitle(); got != tt.wantTitle {
+				t.Errorf("FeedTitle = %q, want %q", got, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestAtomSourceFetchInvalid(t *testing.T) {
+	for name, doc := range map[string]string{
+		"not xml": "{}",
+		"rss":     `<rss version="2.0"><channel><title>RSS</title></channel></rss>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := NewAtomSource(model.Source{FeedURL: serveFeed(t, doc)}, nil)
+
+			if _, err := src.Fetch(context.Background()); !errors.Is(err, ErrParse) {
+				t.Errorf("Fetch error = %v, want ErrParse", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SlyMarbo/rss"
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+var ErrNoFeeds = errors.New("no feeds found")
+
+// commonFeedPaths are tried when a page does not advertise its feeds.
+var commonFeedPaths = []string{
+	"/feed",
+	"/rss",
+	"/feed.xml",
+	"/rss.xml",
+	"/atom.xml",
+	"/index.xml",
+	"/feed.json",
+}
+
+var alternateSelector = cascadia.MustCompile(`link[rel~="alternate"][href]`)
+
+// Candidate is a feed found and verified by Discover.
+type Candidate struct {
+	URL   string
+	Kind  string
+	Title string
+}
+
+// IsFeedKind reports whether kind is a syndication format that Discover can detect.
+func IsFeedKind(kind string) bool {
+	switch kind {
+	case KindRSS, KindAtom, KindJSONFeed:
+		return true
+	}
+
+	return false
+}
+
+// Discover returns the feeds behind rawURL. A feed URL yields itself; for an
+// HTML page the advertised <link rel="alternate"> feeds and common feed paths
+// are tried. Only candidates that download and parse are returned.
+func Discover(ctx context.Context, client *http.Client, rawURL string) ([]Candidate, error) {
+	const op = "source.Discover"
+
+	base, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	data, err := (&httpCache{client: client}).get(ctx, rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if kind, title, ok := DetectKind(data); ok {
+		return []Candidate{{URL: rawURL, Kind: kind, Title: title}}, nil
+	}
+
+	var (
+		links []string
+		seen  = map[string]bool{rawURL: true}
+	)
+
+	for _, link := range discoverLinks(base, data) {
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+
+	// Links are verified concurrently; results keep the discovery order so
+	// advertised feeds come before guessed paths.
+	var (
+		wg       sync.WaitGroup
+		verified = make([]*Candidate, len(links))
+	)
+
+	for i, link := range links {
+		wg.Add(1)
+
+		go func(i int, link string) {
+			defer wg.Done()
+
+			if candidate, ok := verifyFeed(ctx, client, link); ok {
+				verified[i] = &candidate
+			}
+		}(i, link)
+	}
+
+	wg.Wait()
+
+	var candidates []Candidate
+
+	for _, candidate := range verified {
+		if candidate != nil {
+			candidates = append(candidates, *candidate)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrNoFeeds)
+	}
+
+	return candidates, nil
+}
+
+// DetectKind reports which kind of feed data is, along with the feed title.
+func DetectKind(data []byte) (kind string, title string, ok bool) {
+	trimmed := bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		feed, err := parseJSONFeed(trimmed)
+		if err != nil {
+			return "", "", false
+		}
+
+		return KindJSONFeed, feed.Title, true
+	}
+
+	if feed, err := parseAtom(trimmed); err == nil {
+		return KindAtom, feed.Title.String(), true
+	}
+
+	if feed, err := rss.Parse(trimmed); err == nil {
+		return KindRSS, feed.Title, true
+	}
+
+	return "", "", false
+}
+
+// discoverLinks lists feed URLs advertised by an HTML page followed by
+// the common feed paths of its host.
+func discoverLinks(base *url.URL, page []byte) []string {
+	var links []string
+
+	if doc, err := html.Parse(bytes.NewReader(page)); err == nil {
+		for _, node := range alternateSelector.MatchAll(doc) {
+			if !isFeedMIMEType(nodeAttr(node, "type")) {
+				continue
+			}
+
+			if ref, err := url.Parse(nodeAttr(node, "href")); err == nil {
+				links = append(links, base.ResolveReference(ref).String())
+			}
+		}
+	}
+
+	for _, path := range commonFeedPaths {
+		links = append(links, base.ResolveReference(&url.URL{Path: path}).String())
+	}
+
+	return links
+}
+
+func isFeedMIMEType(mimeType string) bool {
+	switch strings.ToLower(strings.TrimSpace(mimeType)) {
+	case "application/rss+xml", "application/atom+xml", "application/feed+json", "application/json":
+		return true
+	}
+
+	return false
+}
+
+func verifyFeed(ctx context.Context, client *http.Client, link string) (Candidate, bool) {
+	data, err := (&httpCache{client: client}).get(ctx, link)
+	if err != nil {
+		return Candidate{}, false
+	}
+
+	kind, title, ok := DetectKind(data)
+	if !ok {
+		return Candidate{}, false
+	}
+
+	return Candidate{URL: link, Kind: kind, Title: title}, true
+}
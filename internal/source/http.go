@@ -0,0 +1,98 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"news-feed-bot/internal/httpclient"
+	"news-feed-bot/internal/model"
+)
+
+// maxFeedSize caps how much of a response body is read into memory.
+const maxFeedSize = 10 << 20
+
+// ErrParse marks documents that were downloaded but could not be parsed.
+var ErrParse = errors.New("failed to parse feed")
+
+// ErrNotModified is returned by Fetch when the server answered a
+// conditional request with 304 Not Modified.
+var ErrNotModified = errors.New("feed not modified")
+
+// httpCache holds the validators of the last response so the next request
+// can be made conditional. Sources embed it to expose CacheValidators.
+type httpCache struct {
+	client       *http.Client
+	etag         string
+	lastModified string
+}
+
+func newHTTPCache(m model.Source, client *http.Client) httpCache {
+	return httpCache{
+		client:       client,
+		etag:         m.ETag,
+		lastModified: m.LastModified,
+	}
+}
+
+// feedTitle remembers the title of the last fetched document. Sources embed
+// it to expose FeedTitle.
+type feedTitle struct {
+	title string
+}
+
+// FeedTitle returns the title of the feed or page seen by the last Fetch.
+func (t *feedTitle) FeedTitle() string {
+	return t.title
+}
+
+// CacheValidators returns the ETag and Last-Modified values of the last
+// successful response.
+func (c *httpCache) CacheValidators() (etag string, lastModified string) {
+	return c.etag, c.lastModified
+}
+
+// get downloads url with If-None-Match / If-Modified-Since taken from the
+// cache and records the validators of a fresh response.
+func (c *httpCache) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.etag != "" {
+		req.Header.Set("If-None-Match", c.etag)
+	}
+	if c.lastModified != "" {
+		req.Header.Set("If-Modified-Since", c.lastModified)
+	}
+
+	client := c.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, ErrNotModified
+	default:
+		return nil, httpclient.NewStatusError(resp)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return nil, err
+	}
+
+	c.etag = resp.Header.Get("ETag")
+	c.lastModified = resp.Header.Get("Last-Modified")
+
+	return data, nil
+}
@@ -0,0 +1,170 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"news-feed-bot/internal/model"
+	"strings"
+	"time"
+)
+
+// JSONFeedSource reads JSON Feed documents (https://www.jsonfeed.org/version/1.1/).
+// Version 1.0 feeds are accepted as well.
+type JSONFeedSource struct {
+	URL        string
+	SourceID   int64
+	SourceName string
+
+	httpCache
+	feedTitle
+}
+
+func NewJSONFeedSource(m model.Source, client *http.Client) *JSONFeedSource {
+	return &JSONFeedSource{
+		URL:        m.FeedURL,
+		SourceID:   m.ID,
+		SourceName: m.Name,
+		httpCache:  newHTTPCache(m, client),
+	}
+}
+
+type jsonFeed struct {
+	Version  string         `json:"version"`
+	Title    string         `json:"title"`
+	HomePage string         `json:"home_page_url"`
+	FeedURL  string         `json:"feed_url"`
+	Language string         `json:"language"`
+	Items    []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	ExternalURL   string           `json:"external_url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Tags          []string         `json:"tags"`
+	Authors       []jsonFeedAuthor `json:"authors"`
+	Author        *jsonFeedAuthor  `json:"author"`
+	Language      string           `json:"language"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func (j *JSONFeedSource) Fetch(ctx context.Context) ([]model.Item, error) {
+	const op = "source.jsonfeed.Fetch"
+
+	data, err := j.get(ctx, j.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	feed, err := parseJSONFeed(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrParse, err)
+	}
+
+	j.title = feed.Title
+
+	var items []model.Item
+
+	for _, item := range feed.Items {
+		items = append(items, model.Item{
+			Title:      strings.TrimSpace(item.Title),
+			Categories: item.Tags,
+			Link:       item.link(),
+			Date:       item.date(),
+			Summary:    item.summary(),
+			Author:     item.author(),
+			SourceName: j.SourceName,
+		})
+	}
+
+	return items, nil
+}
+
+func parseJSONFeed(data []byte) (*jsonFeed, error) {
+	var feed jsonFeed
+
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("parse json feed: %w", err)
+	}
+
+	if !strings.HasPrefix(feed.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("parse json feed: unsupported version %q", feed.Version)
+	}
+
+	return &feed, nil
+}
+
+// link falls back to external_url and then to id, which feeds
+// commonly set to the permalink.
+func (i jsonFeedItem) link() string {
+	switch {
+	case i.URL != "":
+		return i.URL
+	case i.ExternalURL != "":
+		return i.ExternalURL
+	case strings.HasPrefix(i.ID, "http://"), strings.HasPrefix(i.ID, "https://"):
+		return i.ID
+	}
+
+	return ""
+}
+
+func (i jsonFeedItem) date() time.Time {
+	for _, raw := range []string{i.DatePublished, i.DateModified} {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (i jsonFeedItem) summary() string {
+	switch {
+	case i.Summary != "":
+		return i.Summary
+	case i.ContentHTML != "":
+		return i.ContentHTML
+	}
+
+	return i.ContentText
+}
+
+// author joins the names of the authors. The singular author field comes
+// from JSON Feed 1.0.
+func (i jsonFeedItem) author() string {
+	authors := i.Authors
+	if len(authors) == 0 && i.Author != nil {
+		authors = []jsonFeedAuthor{*i.Author}
+	}
+
+	var names []string
+
+	for _, a := range authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func (j *JSONFeedSource) ID() int64 {
+	return j.SourceID
+}
+
+func (j *JSONFeedSource) Name() string {
+	return j.SourceName
+}
@@ -0,0 +1,150 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"news-feed-bot/internal/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJSONFeedSourceFetch(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       string
+		wantTitle string
+		want      []model.Item
+	}{
+		{
+			name: "version 1.1",
+			doc: `{
+				"version": "https://jsonfeed.org/version/1.1",
+				"title": "Example Feed",
+				"items": [
+					{
+						"id": "1",
+						"url": "https://example.com/posts/1",
+						"external_url": "https://elsewhere.com/1",
+						"title": " First post ",
+						"summary": "Short summary",
+						"content_html": "<p>Full content</p>",
+						"date_published": "2024-06-03T09:30:00+02:00",
+						"date_modified": "2024-06-04T10:00:00Z",
+						"tags": ["go", "web"],
+						"authors": [{"name": "Jane Doe"}, {"name": " "}, {"name": "John Roe"}]
+					},
+					{
+						"id": "https://example.com/posts/2",
+						"title": "Second post",
+						"content_html": "<p>Only HTML</p>",
+						"date_modified": "2024-06-05T08:00:00Z"
+					},
+					{
+						"id": "3",
+						"external_url": "https://elsewhere.com/3",
+						"content_text": "Only text",
+						"date_published": "yesterday"
+					},
+					{
+						"id": "not-a-link",
+						"title": "No link"
+					}
+				]
+			}`,
+			wantTitle: "Example Feed",
+			want: []model.Item{
+				{
+					Title:      "First post",
+					Categories: []string{"go", "web"},
+					Link:       "https://example.com/posts/1",
+					Date:       time.Date(2024, time.June, 3, 7, 30, 0, 0, time.UTC),
+					Summary:    "Short summary",
+					Author:     "Jane Doe, John Roe",
+					SourceName: "Example",
+				},
+				{
+					Title:      "Second post",
+					Link:       "https://example.com/posts/2",
+					Date:       time.Date(2024, time.June, 5, 8, 0, 0, 0, time.UTC),
+					Summary:    "<p>Only HTML</p>",
+					SourceName: "Example",
+				},
+				{
+					Link:       "https://elsewhere.com/3",
+					Summary:    "Only text",
+					SourceName: "Example",
+				},
+				{
+					Title:      "No link",
+					SourceName: "Example",
+				},
+			},
+		},
+		{
+			name: "version 1.0 author",
+			doc: `{
+				"version": "https://jsonfeed.org/version/1",
+				"title": "Old Feed",
+				"items": [
+					{
+						"id": "1",
+						"url": "https://example.com/1",
+						"title": "Post",
+						"author": {"name": "Jane Doe"}
+					}
+				]
+			}`,
+			wantTitle: "Old Feed",
+			want: []model.Item{
+				{
+					Title:      "Post",
+					Link:       "https://example.com/1",
+					Author:     "Jane Doe",
+					SourceName: "Example",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewJSONFeedSource(model.Source{FeedURL: serveFeed(t, tt.doc), Name: "Example"}, nil)
+
+			items, err := src.Fetch(context.Background())
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+
+			for i := range items {
+				if !items[i].Date.IsZero() {
+					items[i].Date = items[i].Date.UTC()
+				}
+			}
+
+			if !reflect.DeepEqual(items, tt.want) {
+				t.Errorf("Fetch items =\n%+v\nwant\n%+v", items, tt.want)
+			}
+
+			if got := src.FeedTitle(); got != tt.wantTitle {
+				t.Errorf("FeedTitle = %q, want %q", got, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestJSONFeedSourceFetchInvalid(t *testing.T) {
+	for name, doc := range map[string]string{
+		"not json":        "<feed/>",
+		"missing version": `{"title": "Feed", "items": []}`,
+		"unknown version": `{"version": "2", "items": []}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := NewJSONFeedSource(model.Source{FeedURL: serveFeed(t, doc)}, nil)
+
+			if _, err := src.Fetch(context.Background()); !errors.Is(err, ErrParse) {
+				t.Errorf("Fetch error = %v, want ErrParse", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"news-feed-bot/internal/httpclient"
+	"news-feed-bot/internal/model"
+	"sort"
+)
+
+const (
+	KindRSS      = "rss"
+	KindAtom     = "atom"
+	KindJSONFeed = "jsonfeed"
+	KindScrape   = "scrape"
+	KindTelegram = "telegram"
+)
+
+var ErrUnknownKind = errors.New("unknown source kind")
+
+// Source is a single fetchable feed built from a stored model.Source.
+type Source interface {
+	ID() int64
+	Name() string
+	Fetch(ctx context.Context) ([]model.Item, error)
+}
+
+// Factory builds a Source for one kind from its stored definition. client
+// is configured with the HTTP settings of the source.
+type Factory func(src model.Source, client *http.Client) (Source, error)
+
+// Registry maps source kinds to the factories that build them.
+type Registry struct {
+	factories map[string]Factory
+	clients   *httpclient.Pool
+}
+
+func NewRegistry(clients *httpclient.Pool) *Registry {
+	return &Registry{
+		factories: make(map[string]Factory),
+		clients:   clients,
+	}
+}
+
+// DefaultRegistry returns a registry with all built-in source kinds.
+func DefaultRegistry(clients *httpclient.Pool) *Registry {
+	r := NewRegistry(clients)
+
+	r.Register(KindRSS, func(src model.Source, client *http.Client) (Source, error) {
+		return NewRSSSource(src, client), nil
+	})
+	r.Register(KindAtom, func(src model.Source, client *http.Client) (Source, error) {
+		return NewAtomSource(src, client), nil
+	})
+	r.Register(KindJSONFeed, func(src model.Source, client *http.Client) (Source, error) {
+		return NewJSONFeedSource(src, client), nil
+	})
+	r.Register(KindScrape, func(src model.Source, client *http.Client) (Source, error) {
+		return NewScrapeSource(src, client)
+	})
+	r.Register(KindTelegram, func(src model.Source, client *http.Client) (Source, error) {
+		return NewTelegramSource(src, client), nil
+	})
+
+	return r
+}
+
+func (r *Registry) Register(kind string, factory Factory) {
+	r.factories[kind] = factory
+}
+
+// New builds a Source for src. Sources without a kind are treated as RSS.
+func (r *Registry) New(src model.Source) (Source, error) {
+	const op = "source.Registry.New"
+
+	kind := src.Kind
+	if kind == "" {
+		kind = KindRSS
+	}
+
+	factory, ok := r.factories[kind]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: %q", op, ErrUnknownKind, kind)
+	}
+
+	client, err := r.clients.Client(src.HTTP, src.FeedURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s, err := factory(src, client)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s, nil
+}
+
+// Discover finds the feeds behind the URL of src using its HTTP settings.
+// See the package level Discover.
+func (r *Registry) Discover(ctx context.Context, src model.Source) ([]Candidate, error) {
+	const op = "source.Registry.Discover"
+
+	client, err := r.clients.Client(src.HTTP, src.FeedURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return Discover(ctx, client, src.FeedURL)
+}
+
+func (r *Registry) Has(kind string) bool {
+	_, ok := r.factories[kind]
+	return ok
+}
+
+func (r *Registry) Kinds() []string {
+	kinds := make([]string, 0, len(r.factories))
+	for kind := range r.factories {
+		kinds = append(kinds, kind)
+	}
+
+	sort.Strings(kinds)
+
+	return kinds
+}
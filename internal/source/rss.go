@@ -1,33 +1,51 @@
 package source
 
 import (
+	"bytes"
 	"context"
+	"encoding/xml"
 	"fmt"
 	"github.com/SlyMarbo/rss"
+	"golang.org/x/net/html/charset"
+	"net/http"
 	"news-feed-bot/internal/model"
+	"strings"
 )
 
 type RSSSource struct {
 	URL        string
 	SourceID   int64
 	SourceName string
+
+	httpCache
+	feedTitle
 }
 
-func New(m model.Source) RSSSource {
-	return RSSSource{
+func NewRSSSource(m model.Source, client *http.Client) *RSSSource {
+	return &RSSSource{
 		URL:        m.FeedURL,
 		SourceID:   m.ID,
 		SourceName: m.Name,
+		httpCache:  newHTTPCache(m, client),
 	}
 }
 
-func (r RSSSource) Fetch(ctx context.Context) ([]model.Item, error) {
+func (r *RSSSource) Fetch(ctx context.Context) ([]model.Item, error) {
 	const op = "source.rss.Fetch"
-	feed, err := r.loadFeed(ctx, r.URL)
+	data, err := r.get(ctx, r.URL)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	feed, err := rss.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrParse, err)
+	}
+
+	authors := rssAuthors(data)
+
+	r.title = feed.Title
+
 	var items []model.Item
 
 	for _, item := range feed.Items {
@@ -37,6 +55,7 @@ func (r RSSSource) Fetch(ctx context.Context) ([]model.Item, error) {
 			Link:       item.Link,
 			Date:       item.Date,
 			Summary:    item.Summary,
+			Author:     authors[item.Link],
 			SourceName: r.SourceName,
 		})
 	}
@@ -44,36 +63,47 @@ func (r RSSSource) Fetch(ctx context.Context) ([]model.Item, error) {
 	return items, nil
 }
 
-func (r RSSSource) loadFeed(ctx context.Context, url string) (*rss.Feed, error) {
-	var (
-		feedCh  = make(chan *rss.Feed)
-		errorCh = make(chan error)
-	)
-
-	go func() {
-		feed, err := rss.Fetch(url)
-		if err != nil {
-			errorCh <- err
-			return
-		}
+// rssDocument holds the item authors, which the RSS parser does not expose.
+type rssDocument struct {
+	Items []rssItem `xml:"channel>item"`
+}
 
-		feedCh <- feed
-	}()
+type rssItem struct {
+	Link    string `xml:"link"`
+	Author  string `xml:"author"`
+	Creator string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+}
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case err := <-errorCh:
-		return nil, err
-	case feed := <-feedCh:
-		return feed, nil
+// rssAuthors maps item links to their <author> or <dc:creator>.
+func rssAuthors(data []byte) map[string]string {
+	var doc rssDocument
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	if err := decoder.Decode(&doc); err != nil {
+		return nil
+	}
+
+	authors := make(map[string]string, len(doc.Items))
+
+	for _, item := range doc.Items {
+		author := strings.TrimSpace(item.Creator)
+		if author == "" {
+			author = strings.TrimSpace(item.Author)
+		}
+
+		authors[strings.TrimSpace(item.Link)] = author
 	}
+
+	return authors
 }
 
-func (r RSSSource) ID() int64 {
+func (r *RSSSource) ID() int64 {
 	return r.SourceID
 }
 
-func (r RSSSource) Name() string {
+func (r *RSSSource) Name() string {
 	return r.SourceName
 }
@@ -0,0 +1,233 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/andybalholm/cascadia"
+	"github.com/araddon/dateparse"
+	"golang.org/x/net/html"
+	"net/http"
+	"net/url"
+	"news-feed-bot/internal/model"
+	"strings"
+	"time"
+)
+
+var ErrNoSelectors = errors.New("scrape source requires an item selector")
+
+// ScrapeSource extracts items from a regular web page using CSS selectors.
+type ScrapeSource struct {
+	URL        string
+	SourceID   int64
+	SourceName string
+
+	httpCache
+	feedTitle
+
+	itemSel    cascadia.Selector
+	titleSel   cascadia.Selector
+	linkSel    cascadia.Selector
+	dateSel    cascadia.Selector
+	summarySel cascadia.Selector
+}
+
+func NewScrapeSource(m model.Source, client *http.Client) (*ScrapeSource, error) {
+	const op = "source.scrape.New"
+
+	if m.Selectors == nil || m.Selectors.Item == "" {
+		return nil, fmt.Errorf("%s: %w", op, ErrNoSelectors)
+	}
+
+	s := &ScrapeSource{
+		URL:        m.FeedURL,
+		SourceID:   m.ID,
+		SourceName: m.Name,
+		httpCache:  newHTTPCache(m, client),
+	}
+
+	for _, sel := range []struct {
+		dst *cascadia.Selector
+		src string
+	}{
+		{&s.itemSel, m.Selectors.Item},
+		{&s.titleSel, m.Selectors.Title},
+		{&s.linkSel, m.Selectors.Link},
+		{&s.dateSel, m.Selectors.Date},
+		{&s.summarySel, m.Selectors.Summary},
+	} {
+		if sel.src == "" {
+			continue
+		}
+
+		compiled, err := cascadia.Compile(sel.src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: selector %q: %w", op, sel.src, err)
+		}
+
+		*sel.dst = compiled
+	}
+
+	return s, nil
+}
+
+func (s *ScrapeSource) Fetch(ctx context.Context) ([]model.Item, error) {
+	const op = "source.scrape.Fetch"
+
+	data, err := s.get(ctx, s.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrParse, err)
+	}
+
+	s.title = nodeText(titleSelector.MatchFirst(doc))
+
+	base, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var items []model.Item
+
+	for _, node := range s.itemSel.MatchAll(doc) {
+		item := model.Item{
+			Title:      s.extractTitle(node),
+			Link:       s.extractLink(node, base),
+			Date:       s.extractDate(node),
+			Summary:    s.extractSummary(node),
+			SourceName: s.SourceName,
+		}
+
+		if item.Title == "" || item.Link == "" {
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *ScrapeSource) extractTitle(node *html.Node) string {
+	if s.titleSel == nil {
+		return nodeText(node)
+	}
+
+	return nodeText(s.titleSel.MatchFirst(node))
+}
+
+// extractLink takes the href of the link selector match, or of the
+// first anchor inside the item, and resolves it against the page URL.
+func (s *ScrapeSource) extractLink(node *html.Node, base *url.URL) string {
+	var linkNode *html.Node
+
+	switch {
+	case s.linkSel != nil:
+		linkNode = s.linkSel.MatchFirst(node)
+	case node.Type == html.ElementNode && node.Data == "a":
+		linkNode = node
+	default:
+		linkNode = anchorSelector.MatchFirst(node)
+	}
+
+	href := nodeAttr(linkNode, "href")
+	if href == "" {
+		return ""
+	}
+
+	ref, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+
+	return base.ResolveReference(ref).String()
+}
+
+// extractDate prefers a machine-readable datetime attribute, as found on
+// <time> elements, over the visible text.
+func (s *ScrapeSource) extractDate(node *html.Node) time.Time {
+	if s.dateSel == nil {
+		return time.Time{}
+	}
+
+	dateNode := s.dateSel.MatchFirst(node)
+
+	for _, raw := range []string{nodeAttr(dateNode, "datetime"), nodeText(dateNode)} {
+		if raw == "" {
+			continue
+		}
+
+		if t, err := dateparse.ParseAny(raw); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s *ScrapeSource) extractSummary(node *html.Node) string {
+	if s.summarySel == nil {
+		return ""
+	}
+
+	return nodeText(s.summarySel.MatchFirst(node))
+}
+
+func (s *ScrapeSource) ID() int64 {
+	return s.SourceID
+}
+
+func (s *ScrapeSource) Name() string {
+	return s.SourceName
+}
+
+var (
+	anchorSelector = cascadia.MustCompile("a[href]")
+	titleSelector  = cascadia.MustCompile("head > title")
+)
+
+// nodeText returns the text content of n with whitespace collapsed.
+func nodeText(n *html.Node) string {
+	if n == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+		case n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style"):
+			return
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+func nodeAttr(n *html.Node, name string) string {
+	if n == nil {
+		return ""
+	}
+
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+
+	return ""
+}
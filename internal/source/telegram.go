@@ -0,0 +1,109 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"net/http"
+	"net/url"
+	"news-feed-bot/internal/model"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// telegramTitleLen is how many characters of a post's text are used as its title.
+const telegramTitleLen = 100
+
+// TelegramSource relays posts of a public Telegram channel by scraping
+// its t.me/s/<channel> web preview.
+type TelegramSource struct {
+	URL        string
+	SourceID   int64
+	SourceName string
+
+	httpCache
+	feedTitle
+}
+
+func NewTelegramSource(m model.Source, client *http.Client) *TelegramSource {
+	return &TelegramSource{
+		URL:        m.FeedURL,
+		SourceID:   m.ID,
+		SourceName: m.Name,
+		httpCache:  newHTTPCache(m, client),
+	}
+}
+
+// TelegramChannelURL returns the web preview URL of a public channel.
+func TelegramChannelURL(username string) string {
+	return "https://t.me/s/" + url.PathEscape(strings.TrimPrefix(username, "@"))
+}
+
+var (
+	tgMessageSelector = cascadia.MustCompile(".tgme_widget_message[data-post]")
+	tgTextSelector    = cascadia.MustCompile(".tgme_widget_message_text")
+	tgDateSelector    = cascadia.MustCompile(".tgme_widget_message_date time[datetime]")
+)
+
+func (t *TelegramSource) Fetch(ctx context.Context) ([]model.Item, error) {
+	const op = "source.telegram.Fetch"
+
+	data, err := t.get(ctx, t.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrParse, err)
+	}
+
+	t.title = nodeText(titleSelector.MatchFirst(doc))
+
+	var items []model.Item
+
+	for _, post := range tgMessageSelector.MatchAll(doc) {
+		text := nodeText(tgTextSelector.MatchFirst(post))
+		if text == "" {
+			// Media-only posts have nothing to relay as text.
+			continue
+		}
+
+		date, _ := time.Parse(time.RFC3339, nodeAttr(tgDateSelector.MatchFirst(post), "datetime"))
+
+		items = append(items, model.Item{
+			Title:      telegramTitle(text),
+			Link:       "https://t.me/" + nodeAttr(post, "data-post"),
+			Date:       date,
+			Summary:    text,
+			SourceName: t.SourceName,
+		})
+	}
+
+	return items, nil
+}
+
+// telegramTitle cuts the post text down to a title-sized prefix on a word boundary.
+func telegramTitle(text string) string {
+	if utf8.RuneCountInString(text) <= telegramTitleLen {
+		return text
+	}
+
+	title := string([]rune(text)[:telegramTitleLen])
+	if i := strings.LastIndex(title, " "); i > 0 {
+		title = title[:i]
+	}
+
+	return title + "…"
+}
+
+func (t *TelegramSource) ID() int64 {
+	return t.SourceID
+}
+
+func (t *TelegramSource) Name() string {
+	return t.SourceName
+}
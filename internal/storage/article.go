@@ -32,46 +32,315 @@ func NewArticleStorage(log *slog.Logger) (*ArticlePostgresStorage, error) {
 	return &ArticlePostgresStorage{db: db}, nil
 }
 
-func (s *ArticlePostgresStorage) Store(ctx context.Context, article model.Article) error {
+// Store inserts the article unless it already exists and reports whether
+// a new row was created.
+func (s *ArticlePostgresStorage) Store(ctx context.Context, article model.Article) (bool, error) {
 	const op = "storage.article.Store"
 
 	stmt, err := s.db.Prepare(`INSERT INTO articles
-	                (source_id, title, link, summary, published_at)
-					VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`)
+	                (source_id, title, link, summary, summary_text, published_at, title_hash, language)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT DO NOTHING`)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return false, fmt.Errorf("%s: %w", op, err)
 	}
+	defer stmt.Close()
 
-	if _, err := stmt.ExecContext(
+	res, err := stmt.ExecContext(
 		ctx,
 		article.SourceID,
 		article.Title,
 		article.Link,
 		article.Summary,
+		article.SummaryText,
 		article.PublishedAt,
+		nullableHash(article.TitleHash),
+		article.Language,
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// articleColumns lists the columns scanned by scanArticle, in order.
+const articleColumns = `id, source_id, title, link, summary, published_at, created_at, posted_at,
+	language, content_text, byline, lead_image, word_count, reading_time_seconds,
+	extraction_error, extracted_at`
+
+func scanArticle(row rowScanner) (model.Article, error) {
+	var (
+		article            model.Article
+		readingTimeSeconds int64
+	)
+
+	if err := row.Scan(
+		&article.ID,
+		&article.SourceID,
+		&article.Title,
+		&article.Link,
+		&article.Summary,
+		&article.PublishedAt,
+		&article.CreatedAt,
+		&article.PostedAt,
+		&article.Language,
+		&article.Text,
+		&article.Byline,
+		&article.LeadImage,
+		&article.WordCount,
+		&readingTimeSeconds,
+		&article.ExtractionError,
+		&article.ExtractedAt,
 	); err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return model.Article{}, err
 	}
 
-	return nil
+	article.ReadingTime = time.Duration(readingTimeSeconds) * time.Second
+
+	return article, nil
+}
+
+// queryArticles runs a prepared query selecting articleColumns.
+func (s *ArticlePostgresStorage) queryArticles(ctx context.Context, query string, args ...any) ([]model.Article, error) {
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var articles []model.Article
+
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// SearchArticles returns the articles matching a web search style query,
+// best matches first, along with the total number of matches. Titles weigh
+// more than summaries, and summaries more than the extracted text.
+func (s *ArticlePostgresStorage) SearchArticles(
+	ctx context.Context,
+	query string,
+	limit uint64,
+	offset uint64,
+) ([]model.Article, int, error) {
+	const op = "storage.article.SearchArticles"
+
+	stmt, err := s.db.Prepare(`SELECT ` + articleColumns + `, COUNT(*) OVER ()
+		FROM articles, websearch_to_tsquery('simple', $1) AS query
+		WHERE search_vector @@ query
+		ORDER BY ts_rank_cd(search_vector, query) DESC, published_at DESC
+		LIMIT $2 OFFSET $3`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		articles []model.Article
+		total    int
+	)
+
+	for rows.Next() {
+		article, err := scanArticle(extraColumns{rows, []any{&total}})
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		articles = append(articles, article)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return articles, total, nil
+}
+
+// extraColumns scans columns that follow articleColumns in a row.
+type extraColumns struct {
+	rowScanner
+	extra []any
+}
+
+func (e extraColumns) Scan(dest ...any) error {
+	return e.rowScanner.Scan(append(dest, e.extra...)...)
 }
 
 func (s *ArticlePostgresStorage) NotPostedArticles(
 	ctx context.Context,
+	channelID int64,
 	since time.Time,
 	limit uint64,
+	offset uint64,
 ) ([]model.Article, error) {
 	const op = "storage.article.NotPostedArticles"
 
-	stmt, err := s.db.Prepare(`SELECT * FROM articles
-         WHERE posted_at IS NULL AND published_at > $1 
-         ORDER BY published_at DESC LIMIT $2`)
+	articles, err := s.queryArticles(ctx, `SELECT `+articleColumns+`
+         FROM articles
+         WHERE published_at > $2 AND NOT EXISTS (
+             SELECT 1 FROM channel_posts p WHERE p.channel_id = $1 AND p.article_id = articles.id
+         )
+         ORDER BY published_at DESC, id DESC LIMIT $3 OFFSET $4`,
+		channelID,
+		since,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return articles, nil
+}
+
+// SubscriberArticles returns the articles of the sources a subscriber is
+// subscribed to, directly or through a group, that were not sent to it yet,
+// oldest first. Articles stored before the subscription are left out.
+func (s *ArticlePostgresStorage) SubscriberArticles(
+	ctx context.Context,
+	subscriberID int64,
+	since time.Time,
+	limit uint64,
+) ([]model.Article, error) {
+	const op = "storage.article.SubscriberArticles"
+
+	articles, err := s.queryArticles(ctx, `SELECT `+articleColumns+`
+         FROM articles
+         WHERE created_at > $2
+         AND EXISTS (
+             SELECT 1 FROM subscriptions sub JOIN sources src ON src.id = articles.source_id
+             WHERE sub.subscriber_id = $1 AND sub.created_at <= articles.created_at
+             AND (sub.source_id = src.id
+                 OR (sub.source_group <> '' AND LOWER(sub.source_group) = LOWER(src.source_group)))
+         )
+         AND NOT EXISTS (
+             SELECT 1 FROM subscriber_posts p WHERE p.subscriber_id = $1 AND p.article_id = articles.id
+         )
+         ORDER BY published_at LIMIT $3`,
+		subscriberID,
+		since,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return articles, nil
+}
+
+// LatestArticles returns the most recently stored articles, newest first.
+// A zero source id returns articles of all sources.
+func (s *ArticlePostgresStorage) LatestArticles(ctx context.Context, sourceID int64, limit uint64) ([]model.Article, error) {
+	const op = "storage.article.LatestArticles"
+
+	articles, err := s.queryArticles(ctx, `SELECT `+articleColumns+`
+		FROM articles
+		WHERE $1 = 0 OR source_id = $1
+		ORDER BY created_at DESC LIMIT $2`,
+		sourceID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return articles, nil
+}
+
+// PendingExtraction returns the articles of a source stored since the given
+// time whose content was not extracted yet, oldest first.
+func (s *ArticlePostgresStorage) PendingExtraction(
+	ctx context.Context,
+	sourceID int64,
+	since time.Time,
+	limit uint64,
+) ([]model.Article, error) {
+	const op = "storage.article.PendingExtraction"
+
+	articles, err := s.queryArticles(ctx, `SELECT `+articleColumns+`
+		FROM articles
+		WHERE source_id = $1 AND extracted_at IS NULL AND created_at > $2
+		ORDER BY created_at LIMIT $3`,
+		sourceID,
+		since,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return articles, nil
+}
+
+// SaveExtraction stores the extracted content of an article, or the error
+// that prevented it, and marks the article as processed.
+func (s *ArticlePostgresStorage) SaveExtraction(ctx context.Context, article model.Article) error {
+	const op = "storage.article.SaveExtraction"
+
+	if _, err := s.db.ExecContext(ctx, `UPDATE articles SET
+		content_text = $1,
+		byline = $2,
+		lead_image = $3,
+		word_count = $4,
+		reading_time_seconds = $5,
+		extraction_error = $6,
+		extracted_at = $7
+		WHERE id = $8`,
+		article.Text,
+		article.Byline,
+		article.LeadImage,
+		article.WordCount,
+		int64(article.ReadingTime/time.Second),
+		article.ExtractionError,
+		time.Now().UTC(),
+		article.ID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RecentTitleHashes returns the link and title hash of the articles stored
+// since the given time. Articles without a title hash are left out.
+func (s *ArticlePostgresStorage) RecentTitleHashes(ctx context.Context, since time.Time) ([]model.Article, error) {
+	const op = "storage.article.RecentTitleHashes"
+
+	stmt, err := s.db.Prepare(`SELECT id, source_id, link, title_hash FROM articles
+		WHERE created_at > $1 AND title_hash IS NOT NULL`)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.QueryContext(ctx, since, limit)
+	rows, err := stmt.QueryContext(ctx, since)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -79,21 +348,17 @@ func (s *ArticlePostgresStorage) NotPostedArticles(
 
 	var articles []model.Article
 
-	// Iterate over the rows
 	for rows.Next() {
-		var article model.Article
-		if err := rows.Scan(
-			&article.ID,
-			&article.SourceID,
-			&article.Title,
-			&article.Link,
-			&article.Summary,
-			&article.PublishedAt,
-			&article.CreatedAt,
-			&article.PostedAt,
-		); err != nil {
+		var (
+			article model.Article
+			hash    int64
+		)
+
+		if err := rows.Scan(&article.ID, &article.SourceID, &article.Link, &hash); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
+
+		article.TitleHash = uint64(hash)
 		articles = append(articles, article)
 	}
 
@@ -104,54 +369,145 @@ func (s *ArticlePostgresStorage) NotPostedArticles(
 	return articles, nil
 }
 
-func (s *ArticlePostgresStorage) MarkAsPosted(ctx context.Context, id int64) error {
-	const op = "storage.article.MarkAsPosted"
+// LastPostedBySource maps source ids to the time their latest article was
+// posted to the channel, considering posts since the given time only.
+func (s *ArticlePostgresStorage) LastPostedBySource(
+	ctx context.Context,
+	channelID int64,
+	since time.Time,
+) (map[int64]time.Time, error) {
+	const op = "storage.article.LastPostedBySource"
 
-	stmt, err := s.db.Prepare("UPDATE articles SET posted_at = $1::timestamp WHERE id = $2")
+	stmt, err := s.db.Prepare(`SELECT a.source_id, MAX(p.posted_at)
+		FROM channel_posts p JOIN articles a ON a.id = p.article_id
+		WHERE p.channel_id = $1 AND p.posted_at > $2 GROUP BY a.source_id`)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer stmt.Close()
 
-	if _, err := stmt.ExecContext(ctx,
-		time.Now().UTC().Format(time.RFC3339),
-		id,
-	); err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+	rows, err := stmt.QueryContext(ctx, channelID, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
 
-	return nil
+	lastPosted := make(map[int64]time.Time)
+
+	for rows.Next() {
+		var (
+			sourceID int64
+			postedAt time.Time
+		)
+
+		if err := rows.Scan(&sourceID, &postedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		lastPosted[sourceID] = postedAt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return lastPosted, nil
 }
 
-func (s *ArticlePostgresStorage) ArticlesBySourceID(ctx context.Context, storageID int64) ([]model.Article, error) {
-	const op = "storage.article.ArticlesByStorageID"
+// PostedCountBySource maps source ids to the number of their articles
+// posted to the channel since the given time.
+func (s *ArticlePostgresStorage) PostedCountBySource(
+	ctx context.Context,
+	channelID int64,
+	since time.Time,
+) (map[int64]int, error) {
+	const op = "storage.article.PostedCountBySource"
 
-	stmt, err := s.db.Prepare("SELECT id, source_id, title, link, summary, published_at, created_at FROM articles WHERE source_id = $1")
+	stmt, err := s.db.Prepare(`SELECT a.source_id, COUNT(*)
+		FROM channel_posts p JOIN articles a ON a.id = p.article_id
+		WHERE p.channel_id = $1 AND p.posted_at > $2 GROUP BY a.source_id`)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	defer stmt.Close()
 
-	var articles []model.Article
-	rows, err := stmt.QueryContext(ctx, storageID)
+	rows, err := stmt.QueryContext(ctx, channelID, since)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
 
 	for rows.Next() {
-		var article model.Article
-		if err := rows.Scan(
-			&article.ID,
-			&article.SourceID,
-			&article.Title,
-			&article.Link,
-			&article.Summary,
-			&article.PublishedAt,
-			&article.CreatedAt,
-		); err != nil {
+		var (
+			sourceID int64
+			count    int
+		)
+
+		if err := rows.Scan(&sourceID, &count); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		articles = append(articles, article)
+
+		counts[sourceID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+// MarkAsPosted records that the article was posted to the channel. The
+// posted_at of the article keeps the time of its latest post in any channel.
+func (s *ArticlePostgresStorage) MarkAsPosted(ctx context.Context, channelID int64, id int64) error {
+	const op = "storage.article.MarkAsPosted"
+
+	postedAt := time.Now().UTC().Format(time.RFC3339)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO channel_posts (channel_id, article_id, posted_at)
+		VALUES ($1, $2, $3::timestamp) ON CONFLICT DO NOTHING`,
+		channelID,
+		id,
+		postedAt,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE articles SET posted_at = $1::timestamp WHERE id = $2",
+		postedAt,
+		id,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *ArticlePostgresStorage) ArticlesBySourceID(ctx context.Context, storageID int64) ([]model.Article, error) {
+	const op = "storage.article.ArticlesByStorageID"
+
+	articles, err := s.queryArticles(ctx, "SELECT "+articleColumns+" FROM articles WHERE source_id = $1", storageID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return articles, nil
 }
+
+// nullableHash stores a title hash in a BIGINT column. Zero means the title
+// was too short to hash.
+func nullableHash(hash uint64) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(hash), Valid: hash != 0}
+}
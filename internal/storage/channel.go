@@ -0,0 +1,321 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"news-feed-bot/internal/model"
+	"os"
+	"time"
+)
+
+type ChannelPostgresStorage struct {
+	db *sql.DB
+}
+
+func NewChannelStorage(log *slog.Logger) (*ChannelPostgresStorage, error) {
+	const op = "storage.channel.New"
+
+	log.Info("connecting to db | Channel storage")
+
+	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("connected to db successfully")
+
+	return &ChannelPostgresStorage{db: db}, nil
+}
+
+func (s *ChannelPostgresStorage) Channels(ctx context.Context) ([]model.Channel, error) {
+	const op = "storage.channel.Channels"
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, chat_id, name,
+		digest_mode, digest_time, digest_weekday, digest_size, last_digest_at, created_at
+		FROM channels ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var channels []model.Channel
+
+	for rows.Next() {
+		var (
+			channel      model.Channel
+			lastDigestAt sql.NullTime
+		)
+
+		if err := rows.Scan(
+			&channel.ID,
+			&channel.ChatID,
+			&channel.Name,
+			&channel.Digest.Mode,
+			&channel.Digest.Time,
+			&channel.Digest.Weekday,
+			&channel.Digest.Size,
+			&lastDigestAt,
+			&channel.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		channel.Digest.LastAt = lastDigestAt.Time
+
+		channels = append(channels, channel)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return channels, nil
+}
+
+// AddChannel stores a channel and returns its id. A channel that is already
+// stored keeps its id and gets the new name.
+func (s *ChannelPostgresStorage) AddChannel(ctx context.Context, channel model.Channel) (int64, error) {
+	const op = "storage.channel.AddChannel"
+
+	var id int64
+
+	if err := s.db.QueryRowContext(ctx, `INSERT INTO channels (chat_id, name) VALUES ($1, $2)
+		ON CONFLICT (chat_id) DO UPDATE SET name = EXCLUDED.name RETURNING id`,
+		channel.ChatID,
+		channel.Name,
+	).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// EnsureChannel stores the channel unless it exists. A channel created this
+// way takes over the articles already posted, which went to the single
+// configured channel before channels were stored.
+func (s *ChannelPostgresStorage) EnsureChannel(ctx context.Context, chatID int64, name string) error {
+	const op = "storage.channel.EnsureChannel"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO channels (chat_id, name) VALUES ($1, $2)
+		ON CONFLICT (chat_id) DO NOTHING RETURNING id`,
+		chatID,
+		name,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO channel_posts (channel_id, article_id, posted_at)
+		SELECT $1, id, posted_at FROM articles WHERE posted_at IS NOT NULL`,
+		id,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateDigest stores the digest settings of a channel and reports whether
+// the channel exists. The last digest time is reset to now so a digest whose
+// time of day has already passed today is not posted right away.
+func (s *ChannelPostgresStorage) UpdateDigest(ctx context.Context, id int64, digest model.ChannelDigest) (bool, error) {
+	const op = "storage.channel.UpdateDigest"
+
+	res, err := s.db.ExecContext(ctx, `UPDATE channels SET
+		digest_mode = $1,
+		digest_time = $2,
+		digest_weekday = $3,
+		digest_size = $4,
+		last_digest_at = $5
+		WHERE id = $6`,
+		digest.Mode,
+		digest.Time,
+		digest.Weekday,
+		digest.Size,
+		time.Now().UTC(),
+		id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// CompleteDigest marks the articles of a digest as posted to the channel and
+// records when the digest was posted, in one transaction.
+func (s *ChannelPostgresStorage) CompleteDigest(ctx context.Context, channelID int64, articleIDs []int64, at time.Time) error {
+	const op = "storage.channel.CompleteDigest"
+
+	postedAt := at.UTC().Format(time.RFC3339)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO channel_posts (channel_id, article_id, posted_at)
+		SELECT $1, unnest($2::bigint[]), $3::timestamp ON CONFLICT DO NOTHING`,
+		channelID,
+		pq.Array(articleIDs),
+		postedAt,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE articles SET posted_at = $1::timestamp WHERE id = ANY($2::bigint[])`,
+		postedAt,
+		pq.Array(articleIDs),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE channels SET last_digest_at = $1::timestamp WHERE id = $2`,
+		postedAt,
+		channelID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteChannel removes the channel with its routes and posted state and
+// reports whether it existed.
+func (s *ChannelPostgresStorage) DeleteChannel(ctx context.Context, id int64) (bool, error) {
+	const op = "storage.channel.DeleteChannel"
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM channels WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// Routes returns the routes of all channels.
+func (s *ChannelPostgresStorage) Routes(ctx context.Context) ([]model.ChannelRoute, error) {
+	const op = "storage.channel.Routes"
+
+	stmt, err := s.db.Prepare(`SELECT id, channel_id, source_id, source_group, field, match, pattern, created_at
+		FROM channel_routes ORDER BY channel_id, id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var routes []model.ChannelRoute
+
+	for rows.Next() {
+		var (
+			route    model.ChannelRoute
+			sourceID sql.NullInt64
+		)
+
+		if err := rows.Scan(
+			&route.ID,
+			&route.ChannelID,
+			&sourceID,
+			&route.Group,
+			&route.Field,
+			&route.Match,
+			&route.Pattern,
+			&route.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		route.SourceID = sourceID.Int64
+		routes = append(routes, route)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return routes, nil
+}
+
+func (s *ChannelPostgresStorage) AddRoute(ctx context.Context, route model.ChannelRoute) (int64, error) {
+	const op = "storage.channel.AddRoute"
+
+	stmt, err := s.db.Prepare(`INSERT INTO channel_routes (channel_id, source_id, source_group, field, match, pattern)
+		VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6) RETURNING id`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var id int64
+
+	if err := stmt.QueryRowContext(
+		ctx,
+		route.ChannelID,
+		route.SourceID,
+		route.Group,
+		route.Field,
+		route.Match,
+		route.Pattern,
+	).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// DeleteRoute removes the route and reports whether it existed.
+func (s *ChannelPostgresStorage) DeleteRoute(ctx context.Context, id int64) (bool, error) {
+	const op = "storage.channel.DeleteRoute"
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM channel_routes WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
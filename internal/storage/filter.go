@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	_ "github.com/lib/pq"
+	"log/slog"
+	"news-feed-bot/internal/model"
+	"os"
+)
+
+type FilterPostgresStorage struct {
+	db *sql.DB
+}
+
+func NewFilterStorage(log *slog.Logger) (*FilterPostgresStorage, error) {
+	const op = "storage.filter.New"
+
+	log.Info("connecting to db | Filter storage")
+
+	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("connected to db successfully")
+
+	return &FilterPostgresStorage{db: db}, nil
+}
+
+// Rules returns all filter rules, global ones first.
+func (s *FilterPostgresStorage) Rules(ctx context.Context) ([]model.FilterRule, error) {
+	const op = "storage.filter.Rules"
+
+	stmt, err := s.db.Prepare(`SELECT id, source_id, action, field, match, pattern, created_at
+		FROM filter_rules ORDER BY source_id NULLS FIRST, id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var rules []model.FilterRule
+
+	for rows.Next() {
+		var (
+			rule     model.FilterRule
+			sourceID sql.NullInt64
+		)
+
+		if err := rows.Scan(
+			&rule.ID,
+			&sourceID,
+			&rule.Action,
+			&rule.Field,
+			&rule.Match,
+			&rule.Pattern,
+			&rule.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		rule.SourceID = sourceID.Int64
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules, nil
+}
+
+// AddRule stores rule and returns its id. A zero source id makes the rule global.
+func (s *FilterPostgresStorage) AddRule(ctx context.Context, rule model.FilterRule) (int64, error) {
+	const op = "storage.filter.AddRule"
+
+	stmt, err := s.db.Prepare(`INSERT INTO filter_rules (source_id, action, field, match, pattern)
+		VALUES (NULLIF($1, 0), $2, $3, $4, $5) RETURNING id`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var id int64
+
+	if err := stmt.QueryRowContext(
+		ctx,
+		rule.SourceID,
+		rule.Action,
+		rule.Field,
+		rule.Match,
+		rule.Pattern,
+	).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// DeleteRule removes the rule and reports whether it existed.
+func (s *FilterPostgresStorage) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	const op = "storage.filter.DeleteRule"
+
+	stmt, err := s.db.Prepare(`DELETE FROM filter_rules WHERE id = $1`)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
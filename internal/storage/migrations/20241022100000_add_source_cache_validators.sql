@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE sources ADD COLUMN etag TEXT NOT NULL DEFAULT '';
+ALTER TABLE sources ADD COLUMN last_modified TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sources DROP COLUMN last_modified;
+ALTER TABLE sources DROP COLUMN etag;
+-- +goose StatementEnd
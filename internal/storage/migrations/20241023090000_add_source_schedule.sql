@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE sources ADD COLUMN fetch_interval_seconds BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE sources ADD COLUMN fetch_auto BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE sources ADD COLUMN next_fetch_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sources DROP COLUMN next_fetch_at;
+ALTER TABLE sources DROP COLUMN fetch_auto;
+ALTER TABLE sources DROP COLUMN fetch_interval_seconds;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE sources ADD COLUMN last_success_at TIMESTAMP;
+ALTER TABLE sources ADD COLUMN last_error TEXT NOT NULL DEFAULT '';
+ALTER TABLE sources ADD COLUMN last_error_at TIMESTAMP;
+ALTER TABLE sources ADD COLUMN consecutive_failures INT NOT NULL DEFAULT 0;
+ALTER TABLE sources ADD COLUMN last_new_item_at TIMESTAMP;
+ALTER TABLE sources ADD COLUMN paused BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE sources ADD COLUMN paused_reason TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sources DROP COLUMN paused_reason;
+ALTER TABLE sources DROP COLUMN paused;
+ALTER TABLE sources DROP COLUMN last_new_item_at;
+ALTER TABLE sources DROP COLUMN consecutive_failures;
+ALTER TABLE sources DROP COLUMN last_error_at;
+ALTER TABLE sources DROP COLUMN last_error;
+ALTER TABLE sources DROP COLUMN last_success_at;
+-- +goose StatementEnd
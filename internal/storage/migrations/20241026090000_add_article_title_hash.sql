@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN title_hash BIGINT;
+CREATE INDEX articles_created_at_idx ON articles (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX articles_created_at_idx;
+ALTER TABLE articles DROP COLUMN title_hash;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE filter_rules (
+    id SERIAL PRIMARY KEY,
+    source_id BIGINT REFERENCES sources (id) ON DELETE CASCADE,
+    action TEXT NOT NULL,
+    field TEXT NOT NULL,
+    match TEXT NOT NULL,
+    pattern TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE filter_rules;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN content_text TEXT NOT NULL DEFAULT '';
+ALTER TABLE articles ADD COLUMN byline TEXT NOT NULL DEFAULT '';
+ALTER TABLE articles ADD COLUMN lead_image TEXT NOT NULL DEFAULT '';
+ALTER TABLE articles ADD COLUMN word_count INT NOT NULL DEFAULT 0;
+ALTER TABLE articles ADD COLUMN reading_time_seconds INT NOT NULL DEFAULT 0;
+ALTER TABLE articles ADD COLUMN extraction_error TEXT NOT NULL DEFAULT '';
+ALTER TABLE articles ADD COLUMN extracted_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles DROP COLUMN extracted_at;
+ALTER TABLE articles DROP COLUMN extraction_error;
+ALTER TABLE articles DROP COLUMN reading_time_seconds;
+ALTER TABLE articles DROP COLUMN word_count;
+ALTER TABLE articles DROP COLUMN lead_image;
+ALTER TABLE articles DROP COLUMN byline;
+ALTER TABLE articles DROP COLUMN content_text;
+-- +goose StatementEnd
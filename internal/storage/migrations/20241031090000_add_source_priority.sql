@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE sources ADD COLUMN priority DOUBLE PRECISION NOT NULL DEFAULT 1;
+CREATE INDEX articles_posted_at_idx ON articles (posted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX articles_posted_at_idx;
+ALTER TABLE sources DROP COLUMN priority;
+-- +goose StatementEnd
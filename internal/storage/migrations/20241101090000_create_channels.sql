@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE sources ADD COLUMN source_group TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE channels (
+    id SERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE channel_routes (
+    id SERIAL PRIMARY KEY,
+    channel_id BIGINT NOT NULL REFERENCES channels (id) ON DELETE CASCADE,
+    source_id BIGINT REFERENCES sources (id) ON DELETE CASCADE,
+    source_group TEXT NOT NULL DEFAULT '',
+    field TEXT NOT NULL DEFAULT '',
+    match TEXT NOT NULL DEFAULT '',
+    pattern TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE channel_posts (
+    channel_id BIGINT NOT NULL REFERENCES channels (id) ON DELETE CASCADE,
+    article_id BIGINT NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    posted_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (channel_id, article_id)
+);
+
+CREATE INDEX channel_posts_posted_at_idx ON channel_posts (channel_id, posted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE channel_posts;
+DROP TABLE channel_routes;
+DROP TABLE channels;
+ALTER TABLE sources DROP COLUMN source_group;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE subscribers (
+    id SERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL UNIQUE,
+    username TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    deactivated_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE subscriptions (
+    id SERIAL PRIMARY KEY,
+    subscriber_id BIGINT NOT NULL REFERENCES subscribers (id) ON DELETE CASCADE,
+    source_id BIGINT REFERENCES sources (id) ON DELETE CASCADE,
+    source_group TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX subscriptions_target_idx ON subscriptions (subscriber_id, COALESCE(source_id, 0), LOWER(source_group));
+
+CREATE TABLE subscriber_posts (
+    subscriber_id BIGINT NOT NULL REFERENCES subscribers (id) ON DELETE CASCADE,
+    article_id BIGINT NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    sent_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (subscriber_id, article_id)
+);
+
+CREATE INDEX subscriber_posts_sent_at_idx ON subscriber_posts (subscriber_id, sent_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE subscriber_posts;
+DROP TABLE subscriptions;
+DROP TABLE subscribers;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE channels
+    ADD COLUMN digest_mode TEXT NOT NULL DEFAULT '',
+    ADD COLUMN digest_time TEXT NOT NULL DEFAULT '',
+    ADD COLUMN digest_weekday INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN digest_size INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN last_digest_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE channels
+    DROP COLUMN last_digest_at,
+    DROP COLUMN digest_size,
+    DROP COLUMN digest_weekday,
+    DROP COLUMN digest_time,
+    DROP COLUMN digest_mode;
+-- +goose StatementEnd
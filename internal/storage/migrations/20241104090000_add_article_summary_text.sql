@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN summary_text TEXT NOT NULL DEFAULT '';
+UPDATE articles SET summary_text = regexp_replace(summary, '<[^>]*>', ' ', 'g');
+DROP INDEX articles_search_vector_idx;
+ALTER TABLE articles DROP COLUMN search_vector;
+ALTER TABLE articles ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', summary_text), 'B') ||
+    setweight(to_tsvector('simple', content_text), 'C')
+) STORED;
+CREATE INDEX articles_search_vector_idx ON articles USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX articles_search_vector_idx;
+ALTER TABLE articles DROP COLUMN search_vector;
+ALTER TABLE articles ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(summary, '')), 'B') ||
+    setweight(to_tsvector('simple', content_text), 'C')
+) STORED;
+CREATE INDEX articles_search_vector_idx ON articles USING GIN (search_vector);
+ALTER TABLE articles DROP COLUMN summary_text;
+-- +goose StatementEnd
@@ -3,13 +3,18 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"log/slog"
 	"news-feed-bot/internal/model"
 	"os"
+	"time"
 )
 
+var ErrSourceNotFound = errors.New("source not found")
+
 type SourcePostgresStorage struct {
 	db *sql.DB
 }
@@ -29,44 +34,115 @@ func NewSourceStorage(log *slog.Logger) (*SourcePostgresStorage, error) {
 	return &SourcePostgresStorage{db: db}, nil
 }
 
+// sourceColumns lists the columns scanned by scanSource, in order.
+const sourceColumns = `id, name, feed_url, kind, selectors, http_settings, etag, last_modified,
+	fetch_interval_seconds, fetch_auto, next_fetch_at,
+	last_success_at, last_error, last_error_at, consecutive_failures,
+	last_new_item_at, paused, paused_reason, languages, priority, source_group, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSource(row rowScanner) (model.Source, error) {
+	var (
+		source          model.Source
+		selectors       []byte
+		httpSettings    []byte
+		intervalSeconds int64
+		nextFetchAt     sql.NullTime
+		lastSuccessAt   sql.NullTime
+		lastErrorAt     sql.NullTime
+		lastNewItemAt   sql.NullTime
+		updatedAt       sql.NullTime
+	)
+
+	if err := row.Scan(
+		&source.ID,
+		&source.Name,
+		&source.FeedURL,
+		&source.Kind,
+		&selectors,
+		&httpSettings,
+		&source.ETag,
+		&source.LastModified,
+		&intervalSeconds,
+		&source.AutoInterval,
+		&nextFetchAt,
+		&lastSuccessAt,
+		&source.Health.LastError,
+		&lastErrorAt,
+		&source.Health.ConsecutiveFailures,
+		&lastNewItemAt,
+		&source.Health.Paused,
+		&source.Health.PausedReason,
+		pq.Array(&source.Languages),
+		&source.Priority,
+		&source.Group,
+		&source.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		return model.Source{}, err
+	}
+
+	if selectors != nil {
+		if err := json.Unmarshal(selectors, &source.Selectors); err != nil {
+			return model.Source{}, err
+		}
+	}
+
+	if httpSettings != nil {
+		if err := json.Unmarshal(httpSettings, &source.HTTP); err != nil {
+			return model.Source{}, err
+		}
+	}
+
+	source.FetchInterval = time.Duration(intervalSeconds) * time.Second
+	source.NextFetchAt = nextFetchAt.Time
+	source.Health.LastSuccessAt = lastSuccessAt.Time
+	source.Health.LastErrorAt = lastErrorAt.Time
+	source.Health.LastNewItemAt = lastNewItemAt.Time
+	source.UpdatedAt = updatedAt.Time
+
+	return source, nil
+}
+
 func (s *SourcePostgresStorage) Sources(ctx context.Context) ([]model.Source, error) {
 	const op = "storage.source.Sources"
 
-	stmt, err := s.db.QueryContext(ctx, "SELECT * FROM sources")
+	rows, err := s.db.QueryContext(ctx, "SELECT "+sourceColumns+" FROM sources")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
 
 	var sources []model.Source
 
-	for stmt.Next() {
-		var source model.Source
-		if err := stmt.Scan(&source.ID, &source.Name, &source.FeedURL, &source.CreatedAt, &source.UpdatedAt); err != nil {
+	for rows.Next() {
+		source, err := scanSource(rows)
+		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 		sources = append(sources, source)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	return sources, nil
 }
 
 func (s *SourcePostgresStorage) SourceById(ctx context.Context, id int64) (*model.Source, error) {
 	const op = "storage.source.SourceById"
 
-	stmt, err := s.db.Prepare("SELECT * FROM sources WHERE id = $1")
+	stmt, err := s.db.Prepare("SELECT " + sourceColumns + " FROM sources WHERE id = $1")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer stmt.Close()
 
-	var source model.Source
-
-	err = stmt.QueryRowContext(ctx, id).
-		Scan(
-			&source.ID,
-			&source.Name,
-			&source.FeedURL,
-			&source.CreatedAt,
-		)
+	source, err := scanSource(stmt.QueryRowContext(ctx, id))
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -77,14 +153,38 @@ func (s *SourcePostgresStorage) SourceById(ctx context.Context, id int64) (*mode
 func (s *SourcePostgresStorage) Add(ctx context.Context, source model.Source) (int64, error) {
 	const op = "storage.source.Add"
 
-	stmt, err := s.db.Prepare("INSERT INTO sources(name, feed_url, created_at) VALUES ($1, $2, $3) RETURNING id")
+	selectors, err := nullableJSON(source.Selectors)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	httpSettings, err := nullableJSON(source.HTTP)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.db.Prepare(`INSERT INTO sources
+		(name, feed_url, kind, selectors, http_settings, fetch_interval_seconds, fetch_auto, languages, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`)
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
+	defer stmt.Close()
 
 	var id int64
 
-	err = stmt.QueryRowContext(ctx, source.Name, source.FeedURL, source.CreatedAt).Scan(&id)
+	err = stmt.QueryRowContext(
+		ctx,
+		source.Name,
+		source.FeedURL,
+		source.Kind,
+		selectors,
+		httpSettings,
+		int64(source.FetchInterval/time.Second),
+		source.AutoInterval,
+		pq.Array(languagesOrEmpty(source.Languages)),
+		source.CreatedAt,
+	).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
@@ -92,6 +192,232 @@ func (s *SourcePostgresStorage) Add(ctx context.Context, source model.Source) (i
 	return id, nil
 }
 
+// UpdateCacheValidators stores the ETag and Last-Modified values of the
+// latest feed response for conditional requests.
+func (s *SourcePostgresStorage) UpdateCacheValidators(
+	ctx context.Context,
+	id int64,
+	etag string,
+	lastModified string,
+) error {
+	const op = "storage.source.UpdateCacheValidators"
+
+	if _, err := s.db.ExecContext(
+		ctx,
+		"UPDATE sources SET etag = $1, last_modified = $2 WHERE id = $3",
+		etag,
+		lastModified,
+		id,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateSchedule stores the interval kept for a source and when it is due next.
+func (s *SourcePostgresStorage) UpdateSchedule(
+	ctx context.Context,
+	id int64,
+	interval time.Duration,
+	nextFetchAt time.Time,
+) error {
+	const op = "storage.source.UpdateSchedule"
+
+	if _, err := s.db.ExecContext(
+		ctx,
+		"UPDATE sources SET fetch_interval_seconds = $1, next_fetch_at = $2 WHERE id = $3",
+		int64(interval/time.Second),
+		nextFetchAt,
+		id,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RecordFetchSuccess resets the failure counter of a source and, when the
+// fetch produced new items, remembers when that happened.
+func (s *SourcePostgresStorage) RecordFetchSuccess(
+	ctx context.Context,
+	id int64,
+	at time.Time,
+	hasNewItems bool,
+) error {
+	const op = "storage.source.RecordFetchSuccess"
+
+	if _, err := s.db.ExecContext(ctx, `UPDATE sources SET
+		last_success_at = $1,
+		consecutive_failures = 0,
+		last_new_item_at = CASE WHEN $2::boolean THEN $1 ELSE last_new_item_at END
+		WHERE id = $3`,
+		at,
+		hasNewItems,
+		id,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RecordFetchFailure stores the error of a failed fetch and returns the
+// number of consecutive failures including this one.
+func (s *SourcePostgresStorage) RecordFetchFailure(
+	ctx context.Context,
+	id int64,
+	at time.Time,
+	fetchErr string,
+) (int, error) {
+	const op = "storage.source.RecordFetchFailure"
+
+	var failures int
+
+	if err := s.db.QueryRowContext(ctx, `UPDATE sources SET
+		last_error = $1,
+		last_error_at = $2,
+		consecutive_failures = consecutive_failures + 1
+		WHERE id = $3 RETURNING consecutive_failures`,
+		fetchErr,
+		at,
+		id,
+	).Scan(&failures); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return failures, nil
+}
+
+func (s *SourcePostgresStorage) PauseSource(ctx context.Context, id int64, reason string) error {
+	const op = "storage.source.PauseSource"
+
+	if _, err := s.db.ExecContext(
+		ctx,
+		"UPDATE sources SET paused = TRUE, paused_reason = $1 WHERE id = $2",
+		reason,
+		id,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ResumeSource re-enables a paused source and makes it due immediately.
+// The staleness clock restarts so the source is not paused again right away.
+func (s *SourcePostgresStorage) ResumeSource(ctx context.Context, id int64) error {
+	const op = "storage.source.ResumeSource"
+
+	res, err := s.db.ExecContext(ctx, `UPDATE sources SET
+		paused = FALSE,
+		paused_reason = '',
+		consecutive_failures = 0,
+		last_new_item_at = $1,
+		next_fetch_at = NULL
+		WHERE id = $2`,
+		time.Now().UTC(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrSourceNotFound)
+	}
+
+	return nil
+}
+
+// UpdateLanguages replaces the languages allowed for the items of a source.
+// An empty list makes the source follow the global list.
+func (s *SourcePostgresStorage) UpdateLanguages(ctx context.Context, id int64, languages []string) error {
+	const op = "storage.source.UpdateLanguages"
+
+	res, err := s.db.ExecContext(ctx, `UPDATE sources SET languages = $1 WHERE id = $2`,
+		pq.Array(languagesOrEmpty(languages)),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrSourceNotFound)
+	}
+
+	return nil
+}
+
+// UpdatePriority sets the weight of the articles of a source in the selection
+// of the next post.
+func (s *SourcePostgresStorage) UpdatePriority(ctx context.Context, id int64, priority float64) error {
+	const op = "storage.source.UpdatePriority"
+
+	res, err := s.db.ExecContext(ctx, `UPDATE sources SET priority = $1 WHERE id = $2`, priority, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrSourceNotFound)
+	}
+
+	return nil
+}
+
+// UpdateGroup puts a source in a group that channel routes can refer to.
+// An empty group removes it from its group.
+func (s *SourcePostgresStorage) UpdateGroup(ctx context.Context, id int64, group string) error {
+	const op = "storage.source.UpdateGroup"
+
+	res, err := s.db.ExecContext(ctx, `UPDATE sources SET source_group = $1 WHERE id = $2`, group, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrSourceNotFound)
+	}
+
+	return nil
+}
+
+// nullableJSON encodes v for a JSONB column, storing SQL NULL for nil pointers.
+func nullableJSON[T any](v *T) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
 func (s *SourcePostgresStorage) Delete(ctx context.Context, id int64) error {
 	const op = "storage.source.Delete"
 
@@ -102,3 +428,12 @@ func (s *SourcePostgresStorage) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// languagesOrEmpty keeps a nil list from being stored as NULL.
+func languagesOrEmpty(languages []string) []string {
+	if languages == nil {
+		return []string{}
+	}
+
+	return languages
+}
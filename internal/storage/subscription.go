@@ -0,0 +1,231 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	_ "github.com/lib/pq"
+	"log/slog"
+	"news-feed-bot/internal/model"
+	"os"
+	"time"
+)
+
+type SubscriptionPostgresStorage struct {
+	db *sql.DB
+}
+
+func NewSubscriptionStorage(log *slog.Logger) (*SubscriptionPostgresStorage, error) {
+	const op = "storage.subscription.New"
+
+	log.Info("connecting to db | Subscription storage")
+
+	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("connected to db successfully")
+
+	return &SubscriptionPostgresStorage{db: db}, nil
+}
+
+// Subscribe stores the subscription of a chat and reports whether it is
+// new. The subscriber is created, or reactivated if it blocked the bot before.
+func (s *SubscriptionPostgresStorage) Subscribe(
+	ctx context.Context,
+	chatID int64,
+	username string,
+	sub model.Subscription,
+) (bool, error) {
+	const op = "storage.subscription.Subscribe"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var subscriberID int64
+
+	if err := tx.QueryRowContext(ctx, `INSERT INTO subscribers (chat_id, username) VALUES ($1, $2)
+		ON CONFLICT (chat_id) DO UPDATE SET
+			username = EXCLUDED.username,
+			active = TRUE,
+			deactivated_at = NULL
+		RETURNING id`,
+		chatID,
+		username,
+	).Scan(&subscriberID); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO subscriptions (subscriber_id, source_id, source_group)
+		VALUES ($1, NULLIF($2, 0), $3) ON CONFLICT DO NOTHING`,
+		subscriberID,
+		sub.SourceID,
+		sub.Group,
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// Unsubscribe removes the subscription of a chat to a source or a group
+// and reports whether it existed.
+func (s *SubscriptionPostgresStorage) Unsubscribe(ctx context.Context, chatID int64, sub model.Subscription) (bool, error) {
+	const op = "storage.subscription.Unsubscribe"
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM subscriptions s USING subscribers u
+		WHERE s.subscriber_id = u.id AND u.chat_id = $1
+			AND COALESCE(s.source_id, 0) = $2 AND LOWER(s.source_group) = LOWER($3)`,
+		chatID,
+		sub.SourceID,
+		sub.Group,
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// Subscriptions returns the subscriptions of a chat.
+func (s *SubscriptionPostgresStorage) Subscriptions(ctx context.Context, chatID int64) ([]model.Subscription, error) {
+	const op = "storage.subscription.Subscriptions"
+
+	rows, err := s.db.QueryContext(ctx, `SELECT s.id, s.subscriber_id, s.source_id, s.source_group, s.created_at
+		FROM subscriptions s JOIN subscribers u ON u.id = s.subscriber_id
+		WHERE u.chat_id = $1 ORDER BY s.id`,
+		chatID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var subscriptions []model.Subscription
+
+	for rows.Next() {
+		var (
+			sub      model.Subscription
+			sourceID sql.NullInt64
+		)
+
+		if err := rows.Scan(&sub.ID, &sub.SubscriberID, &sourceID, &sub.Group, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		sub.SourceID = sourceID.Int64
+		subscriptions = append(subscriptions, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return subscriptions, nil
+}
+
+// ActiveSubscribers returns the subscribers with at least one subscription
+// that have not blocked the bot.
+func (s *SubscriptionPostgresStorage) ActiveSubscribers(ctx context.Context) ([]model.Subscriber, error) {
+	const op = "storage.subscription.ActiveSubscribers"
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, chat_id, username, active, created_at FROM subscribers u
+		WHERE active AND EXISTS (SELECT 1 FROM subscriptions s WHERE s.subscriber_id = u.id)
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var subscribers []model.Subscriber
+
+	for rows.Next() {
+		var subscriber model.Subscriber
+
+		if err := rows.Scan(
+			&subscriber.ID,
+			&subscriber.ChatID,
+			&subscriber.Username,
+			&subscriber.Active,
+			&subscriber.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		subscribers = append(subscribers, subscriber)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return subscribers, nil
+}
+
+// SentCount returns the number of articles sent to the subscriber since the
+// given time.
+func (s *SubscriptionPostgresStorage) SentCount(ctx context.Context, subscriberID int64, since time.Time) (int, error) {
+	const op = "storage.subscription.SentCount"
+
+	var count int
+
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM subscriber_posts WHERE subscriber_id = $1 AND sent_at > $2`,
+		subscriberID,
+		since,
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (s *SubscriptionPostgresStorage) MarkSent(ctx context.Context, subscriberID int64, articleID int64) error {
+	const op = "storage.subscription.MarkSent"
+
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO subscriber_posts (subscriber_id, article_id, sent_at)
+		VALUES ($1, $2, $3::timestamp) ON CONFLICT DO NOTHING`,
+		subscriberID,
+		articleID,
+		time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Deactivate stops deliveries to a subscriber that blocked the bot. Its
+// subscriptions are kept for when it subscribes again.
+func (s *SubscriptionPostgresStorage) Deactivate(ctx context.Context, subscriberID int64) error {
+	const op = "storage.subscription.Deactivate"
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE subscribers SET active = FALSE, deactivated_at = $1 WHERE id = $2`,
+		time.Now().UTC(),
+		subscriberID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"log/slog"
+	"net/http"
+	"news-feed-bot/internal/botkit/markup"
+	"news-feed-bot/internal/model"
+	"time"
+)
+
+type ArticleProvider interface {
+	SubscriberArticles(ctx context.Context, subscriberID int64, since time.Time, limit uint64) ([]model.Article, error)
+}
+
+type SubscriberStorage interface {
+	ActiveSubscribers(ctx context.Context) ([]model.Subscriber, error)
+	SentCount(ctx context.Context, subscriberID int64, since time.Time) (int, error)
+	MarkSent(ctx context.Context, subscriberID int64, articleID int64) error
+	Deactivate(ctx context.Context, subscriberID int64) error
+}
+
+type SourceProvider interface {
+	Sources(ctx context.Context) ([]model.Source, error)
+}
+
+// RateLimit caps the articles sent to each subscriber.
+type RateLimit struct {
+	PerTick int
+	PerHour int
+}
+
+// Deliverer sends the articles of subscribed sources to subscribers in
+// private chats.
+type Deliverer struct {
+	articles    ArticleProvider
+	subscribers SubscriberStorage
+	sources     SourceProvider
+	bot         *tgbotapi.BotAPI
+	interval    time.Duration
+	lookback    time.Duration
+	limit       RateLimit
+	log         *slog.Logger
+}
+
+// New builds a deliverer sending articles stored within lookback every
+// interval.
+func New(
+	articles ArticleProvider,
+	subscribers SubscriberStorage,
+	sources SourceProvider,
+	bot *tgbotapi.BotAPI,
+	interval time.Duration,
+	lookback time.Duration,
+	limit RateLimit,
+	log *slog.Logger,
+) *Deliverer {
+	return &Deliverer{
+		articles:    articles,
+		subscribers: subscribers,
+		sources:     sources,
+		bot:         bot,
+		interval:    interval,
+		lookback:    lookback,
+		limit:       limit,
+		log:         log,
+	}
+}
+
+func (d *Deliverer) Start(ctx context.Context) error {
+	const op = "subscription.Deliverer.Start"
+
+	d.log.Info("subscription deliverer was started successfully")
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.Deliver(ctx); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w", op, ctx.Err())
+		}
+	}
+}
+
+// Deliver sends pending articles to every active subscriber within its rate
+// limit. A failing subscriber is logged and does not hold back the others.
+func (d *Deliverer) Deliver(ctx context.Context) error {
+	const op = "subscription.Deliverer.Deliver"
+
+	subscribers, err := d.subscribers.ActiveSubscribers(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(subscribers) == 0 {
+		return nil
+	}
+
+	sources, err := d.sources.Sources(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	sourceNames := make(map[int64]string, len(sources))
+	for _, src := range sources {
+		sourceNames[src.ID] = src.Name
+	}
+
+	for _, subscriber := range subscribers {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := d.deliverTo(ctx, subscriber, sourceNames, time.Now()); err != nil {
+			d.log.Error("failed to deliver articles to subscriber",
+				"subscriber_id", subscriber.ID,
+				"err", err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (d *Deliverer) deliverTo(
+	ctx context.Context,
+	subscriber model.Subscriber,
+	sourceNames map[int64]string,
+	now time.Time,
+) error {
+	quota := max(d.limit.PerTick, 1)
+
+	if d.limit.PerHour > 0 {
+		sent, err := d.subscribers.SentCount(ctx, subscriber.ID, now.Add(-time.Hour))
+		if err != nil {
+			return err
+		}
+
+		quota = min(quota, d.limit.PerHour-sent)
+	}
+
+	if quota <= 0 {
+		return nil
+	}
+
+	articles, err := d.articles.SubscriberArticles(ctx, subscriber.ID, now.Add(-d.lookback), uint64(quota))
+	if err != nil {
+		return err
+	}
+
+	for _, article := range articles {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err := d.send(subscriber.ChatID, article, sourceNames[article.SourceID]); err != nil {
+			if !isBlocked(err) {
+				return err
+			}
+
+			d.log.Info("subscriber blocked the bot, deactivating",
+				"subscriber_id", subscriber.ID,
+				"err", err,
+			)
+
+			return d.subscribers.Deactivate(context.WithoutCancel(ctx), subscriber.ID)
+		}
+
+		// A sent article is marked even when ctx is cancelled meanwhile,
+		// so nobody gets it twice after a restart.
+		if err := d.subscribers.MarkSent(context.WithoutCancel(ctx), subscriber.ID, article.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Deliverer) send(chatID int64, article model.Article, sourceName string) error {
+	const msgFormat = "*%s*\n_%s_\n\n%s"
+
+	// An empty name would leave "__", which MarkdownV2 rejects.
+	if sourceName == "" {
+		sourceName = fmt.Sprintf("Source %d", article.SourceID)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(
+		msgFormat,
+		markup.EscapeForMarkdown(article.Title),
+		markup.EscapeForMarkdown(sourceName),
+		markup.EscapeForMarkdown(article.Link),
+	))
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+
+	_, err := d.bot.Send(msg)
+
+	return err
+}
+
+// isBlocked reports whether Telegram refused the message because the user
+// blocked the bot or deleted their account.
+func isBlocked(err error) bool {
+	var apiErr *tgbotapi.Error
+
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden
+}
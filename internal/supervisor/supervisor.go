@@ -0,0 +1,184 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTooManyRestarts  = errors.New("component keeps crashing")
+	ErrShutdownDeadline = errors.New("shutdown deadline exceeded")
+)
+
+// RunFunc runs a component until ctx is cancelled. Work in progress at that
+// moment should be finished before returning.
+type RunFunc func(ctx context.Context) error
+
+// Policy controls restarts of failed components and the shutdown.
+type Policy struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxRestarts within RestartWindow make the supervisor give up.
+	MaxRestarts   int
+	RestartWindow time.Duration
+	// ShutdownTimeout is how long components may take to stop.
+	ShutdownTimeout time.Duration
+}
+
+type component struct {
+	name string
+	run  RunFunc
+}
+
+// Supervisor runs long-lived components, restarting the ones that fail.
+type Supervisor struct {
+	policy     Policy
+	components []component
+	log        *slog.Logger
+}
+
+func New(policy Policy, log *slog.Logger) *Supervisor {
+	return &Supervisor{
+		policy: policy,
+		log:    log,
+	}
+}
+
+func (s *Supervisor) Add(name string, run RunFunc) {
+	s.components = append(s.components, component{name: name, run: run})
+}
+
+// Run starts all components and blocks until ctx is cancelled and every
+// component has stopped, or until a component exceeds its restart budget,
+// in which case the others are stopped as well. It returns an error if a
+// component gave up or the shutdown did not finish in time.
+func (s *Supervisor) Run(ctx context.Context) error {
+	const op = "supervisor.Run"
+
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		runErr  error
+	)
+
+	for _, c := range s.components {
+		wg.Add(1)
+
+		go func(c component) {
+			defer wg.Done()
+
+			if err := s.supervise(runCtx, c); err != nil {
+				errOnce.Do(func() { runErr = err })
+				stop()
+			}
+		}(c)
+	}
+
+	<-runCtx.Done()
+
+	s.log.Info("stopping components", "timeout", s.policy.ShutdownTimeout)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(s.policy.ShutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		s.log.Info("all components stopped")
+	case <-timer.C:
+		return fmt.Errorf("%s: %w", op, ErrShutdownDeadline)
+	}
+
+	if runErr != nil {
+		return fmt.Errorf("%s: %w", op, runErr)
+	}
+
+	return nil
+}
+
+// supervise runs c until ctx is cancelled, restarting it with exponential
+// backoff whenever it fails or returns on its own.
+func (s *Supervisor) supervise(ctx context.Context, c component) error {
+	var (
+		restarts []time.Time
+		backoff  = s.policy.InitialBackoff
+	)
+
+	for {
+		startedAt := time.Now()
+
+		err := runSafely(ctx, c.run)
+		if ctx.Err() != nil {
+			s.log.Info("component stopped", "component", c.name)
+			return nil
+		}
+
+		if err == nil {
+			err = errors.New("returned unexpectedly")
+		}
+
+		// A component that ran for a while before failing starts over
+		// with the initial backoff.
+		if time.Since(startedAt) > s.policy.MaxBackoff {
+			backoff = s.policy.InitialBackoff
+		}
+
+		now := time.Now()
+		restarts = append(restarts, now)
+		for len(restarts) > 0 && now.Sub(restarts[0]) > s.policy.RestartWindow {
+			restarts = restarts[1:]
+		}
+
+		if len(restarts) > s.policy.MaxRestarts {
+			s.log.Error("component keeps crashing, giving up",
+				"component", c.name,
+				"restarts", len(restarts)-1,
+				"window", s.policy.RestartWindow,
+				"err", err,
+			)
+
+			return fmt.Errorf("%s: %w: %w", c.name, ErrTooManyRestarts, err)
+		}
+
+		s.log.Error("component failed, restarting",
+			"component", c.name,
+			"backoff", backoff,
+			"err", err,
+		)
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		}
+
+		backoff = min(backoff*2, s.policy.MaxBackoff)
+	}
+}
+
+// runSafely turns a panic of run into an error so it is restarted like any
+// other failure.
+func runSafely(ctx context.Context, run RunFunc) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	return run(ctx)
+}
@@ -0,0 +1,173 @@
+package urlutil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query parameters that only identify the referrer of a
+// click. Parameters starting with "utm_" are dropped as well.
+var trackingParams = map[string]bool{
+	"fbclid":   true,
+	"gclid":    true,
+	"dclid":    true,
+	"yclid":    true,
+	"msclkid":  true,
+	"igshid":   true,
+	"mc_cid":   true,
+	"mc_eid":   true,
+	"_hsenc":   true,
+	"_hsmi":    true,
+	"mkt_tok":  true,
+	"ref_src":  true,
+	"ref_url":  true,
+	"cmpid":    true,
+	"ncid":     true,
+	"sr_share": true,
+	"spm":      true,
+}
+
+// redirectHosts serve links that only redirect to the article itself.
+var redirectHosts = map[string]bool{
+	"feedproxy.google.com":    true,
+	"feeds.feedburner.com":    true,
+	"t.co":                    true,
+	"bit.ly":                  true,
+	"buff.ly":                 true,
+	"dlvr.it":                 true,
+	"ow.ly":                   true,
+	"trib.al":                 true,
+	"lnkd.in":                 true,
+	"tinyurl.com":             true,
+	"news.google.com":         true,
+	"rss.feedsportal.com":     true,
+	"feeds.feedblitz.com":     true,
+	"feedproxy.feedblitz.com": true,
+}
+
+// Canonicalize returns the form of an article link that is stored: the
+// scheme and host are lowercased, default ports and fragments are dropped
+// and tracking parameters are removed. The remaining parameters are kept
+// as written, in their order, since servers may depend on either. URLs
+// that do not parse are returned trimmed.
+func Canonicalize(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	cleanHost(u)
+
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	u.RawQuery = stripTrackingParams(u.RawQuery)
+	u.ForceQuery = false
+
+	return u.String()
+}
+
+// stripTrackingParams drops tracking parameters from a raw query string
+// and leaves the others untouched.
+func stripTrackingParams(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	params := strings.Split(rawQuery, "&")
+	kept := params[:0]
+
+	for _, param := range params {
+		if param == "" {
+			continue
+		}
+
+		key, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+
+		if !isTrackingParam(key) {
+			kept = append(kept, param)
+		}
+	}
+
+	return strings.Join(kept, "&")
+}
+
+// IsRedirect reports whether rawURL points at a known redirecting service
+// such as a feed proxy or a link shortener.
+func IsRedirect(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	return redirectHosts[strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")]
+}
+
+// ResolveRedirects follows the redirects of rawURL and returns the URL it
+// finally lands on.
+func ResolveRedirects(ctx context.Context, client *http.Client, rawURL string) (string, error) {
+	const op = "urlutil.ResolveRedirects"
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	final, err := followRedirects(ctx, client, http.MethodHead, rawURL)
+	if err != nil {
+		// Some services refuse HEAD requests, give GET a try before failing.
+		final, err = followRedirects(ctx, client, http.MethodGet, rawURL)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return final, nil
+}
+
+func followRedirects(ctx context.Context, client *http.Client, method string, rawURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	// Only the final URL is needed, the body is left unread.
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.Request.URL.String(), nil
+}
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+
+	return strings.HasPrefix(key, "utm_") || trackingParams[key]
+}
+
+// cleanHost lowercases the scheme and host of u and drops a default port.
+func cleanHost(u *url.URL) {
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+
+	switch {
+	case u.Scheme == "http" && strings.HasSuffix(u.Host, ":80"):
+		u.Host = strings.TrimSuffix(u.Host, ":80")
+	case u.Scheme == "https" && strings.HasSuffix(u.Host, ":443"):
+		u.Host = strings.TrimSuffix(u.Host, ":443")
+	}
+}
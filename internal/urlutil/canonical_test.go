@@ -0,0 +1,38 @@
+package urlutil
+
+import "testing"
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"unchanged", "https://example.com/news/1", "https://example.com/news/1"},
+		{"trims spaces", "  https://example.com/a  ", "https://example.com/a"},
+		{"lowercases scheme and host", "HTTPS://Example.COM/Path", "https://example.com/Path"},
+		{"drops http default port", "http://example.com:80/a", "http://example.com/a"},
+		{"drops https default port", "https://example.com:443/a", "https://example.com/a"},
+		{"keeps other ports", "https://example.com:8443/a", "https://example.com:8443/a"},
+		{"drops fragment", "https://example.com/a#comments", "https://example.com/a"},
+		{"drops utm params", "https://example.com/a?utm_source=rss&utm_medium=feed", "https://example.com/a"},
+		{"drops tracking params ignoring case", "https://example.com/a?FBCLID=x&Utm_Campaign=y", "https://example.com/a"},
+		{"drops escaped tracking key", "https://example.com/a?utm%5Fsource=rss&id=1", "https://example.com/a?id=1"},
+		{"keeps order of other params", "https://example.com/a?b=2&utm_source=rss&a=1", "https://example.com/a?b=2&a=1"},
+		{"keeps escaping of other params", "https://example.com/s?q=a%20b&x=%2F&gclid=1", "https://example.com/s?q=a%20b&x=%2F"},
+		{"keeps repeated params", "https://example.com/a?tag=x&tag=y", "https://example.com/a?tag=x&tag=y"},
+		{"keeps params without value", "https://example.com/a?print&spm=1", "https://example.com/a?print"},
+		{"drops empty params", "https://example.com/a?&id=1&&", "https://example.com/a?id=1"},
+		{"drops empty query", "https://example.com/a?", "https://example.com/a"},
+		{"no host", "/relative/path", "/relative/path"},
+		{"unparsable", "http://exa mple.com/%zz", "http://exa mple.com/%zz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Canonicalize(tt.url); got != tt.want {
+				t.Errorf("Canonicalize(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package urlutil
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Normalize returns a form of rawURL suitable for comparing two URLs that
+// point at the same resource: the scheme and host are lowercased, default
+// ports, fragments and trailing slashes are dropped and query parameters
+// are sorted. URLs that do not parse are returned trimmed.
+func Normalize(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	cleanHost(u)
+
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	u.RawQuery = u.Query().Encode()
+
+	return u.String()
+}